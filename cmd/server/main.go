@@ -8,10 +8,14 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Time zones for the tz query parameter on hosts without zoneinfo
 
+	"github.com/pizza-nz/restaurant-service/internal/api"
 	"github.com/pizza-nz/restaurant-service/internal/config"
 	"github.com/pizza-nz/restaurant-service/internal/db"
 	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/middleware"
+	"github.com/pizza-nz/restaurant-service/internal/models"
 	"github.com/pizza-nz/restaurant-service/internal/router"
 	"github.com/pizza-nz/restaurant-service/internal/service"
 	"github.com/pizza-nz/restaurant-service/internal/websockets"
@@ -24,6 +28,35 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Fail fast if migrations can't be found before touching the database
+	if err := db.VerifyMigrations(cfg.Database); err != nil {
+		log.Fatalf("Invalid migrations configuration: %v", err)
+	}
+	if err := service.SetOrderNumberFormat(cfg.Orders.NumberFormat); err != nil {
+		log.Fatalf("Invalid orders configuration: %v", err)
+	}
+	if err := service.SetBusinessDayCutoff(cfg.Orders.BusinessDayCutoff); err != nil {
+		log.Fatalf("Invalid orders configuration: %v", err)
+	}
+	if err := models.SetCurrencyMinorUnits(*cfg.Orders.CurrencyMinorUnits); err != nil {
+		log.Fatalf("Invalid orders configuration: %v", err)
+	}
+	api.SetMaxBodyBytes(cfg.Server.MaxBodyBytes)
+	if err := models.SetDisplayOfflineAfter(time.Duration(cfg.WebSocket.DisplayOfflineAfter) * time.Second); err != nil {
+		log.Fatalf("Invalid websocket configuration: %v", err)
+	}
+	if err := service.SetMaxModifierOptions(cfg.Menu.MaxModifierOptions); err != nil {
+		log.Fatalf("Invalid menu configuration: %v", err)
+	}
+
+	providers := make(map[string]service.IntegrationProvider, len(cfg.Integrations.Providers))
+	for name, p := range cfg.Integrations.Providers {
+		providers[name] = service.IntegrationProvider{Secret: p.Secret, Username: p.Username}
+	}
+	if err := service.SetIntegrationProviders(providers); err != nil {
+		log.Fatalf("Invalid integrations configuration: %v", err)
+	}
+
 	// Initialize database
 	database, err := db.NewPostgres(cfg.Database)
 	if err != nil {
@@ -36,24 +69,67 @@ func main() {
 		log.Fatalf("Failed to run database migrations: %v", err)
 	}
 
-	// Initialize Factory
-	// TODO: Refactory this to use db.Postgres
-	factory := repository.NewRepositories(database)
+	// Initialize repositories
+	repos := repository.NewRepositories(database)
+	repos.Order.SetAutoComplete(*cfg.Orders.AutoComplete)
+
+	// Apply WebSocket upgrade settings before any connection is accepted
+	websockets.SetBufferSizes(cfg.WebSocket.ReadBufferSize, cfg.WebSocket.WriteBufferSize)
+	websockets.SetAllowedOrigins(cfg.WebSocket.AllowedOrigins)
+	if cfg.WebSocket.EnableCompression {
+		websockets.EnableCompression()
+	}
 
 	// Initialize WebSocket hub
 	hub := websockets.NewHub()
 	go hub.Run()
 
 	// Initialize Auth Service
-	authService := service.NewAuthService(factory, service.JWTConfig(cfg.JWT))
+	authService, err := service.NewAuthService(repos, service.JWTConfig(cfg.JWT))
+	if err != nil {
+		log.Fatalf("Failed to initialize auth service: %v", err)
+	}
+
+	// Track display liveness so managers hear about frozen screens
+	displayService := service.NewDisplayService(repos, hub)
+	hub.SetDisplayHeartbeatHandler(displayService.RecordHeartbeat)
+
+	// Let bump bars on KDS sockets act on station items
+	stationService := service.NewStationService(repos, hub)
+	hub.SetStationActionHandler(stationService.HandleSocketAction)
+
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	go displayService.MonitorHeartbeats(monitorCtx, time.Duration(cfg.WebSocket.DisplayCheckInterval)*time.Second)
+
+	// Clear out orders that were opened and abandoned
+	if cfg.Orders.AutoCancelEnabled {
+		orderService := service.NewOrderService(repos)
+		go orderService.RunAutoCancel(
+			monitorCtx,
+			hub,
+			time.Duration(cfg.Orders.AutoCancelAfter)*time.Minute,
+			time.Duration(cfg.Orders.AutoCancelInterval)*time.Second,
+		)
+	}
 
 	// Initialize router
-	r := router.New(factory, authService, hub)
+	r := router.New(
+		repos,
+		authService,
+		hub,
+		time.Duration(cfg.Database.QueryTimeout)*time.Second,
+		time.Duration(cfg.Database.ExportTimeout)*time.Second,
+	)
 
 	// Create HTTP server
 	server := &http.Server{
-		Addr:    cfg.Server.Address,
-		Handler: r,
+		Addr:              cfg.Server.Address,
+		Handler:           middleware.CORS(cfg.Server.CORSAllowedOrigins)(r),
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeout) * time.Second,
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeout) * time.Second,
 	}
 
 	// Start server in a goroutine
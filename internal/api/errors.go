@@ -2,6 +2,68 @@ package api
 
 import "net/http"
 
+// ErrorBody is the detail inside an error response
+type ErrorBody struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// ErrorResponse is the JSON envelope for every error response
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// Error writes a JSON error envelope with the given status and code
+func Error(w http.ResponseWriter, status int, code, message string) {
+	JSON(w, status, ErrorResponse{Error: ErrorBody{Code: code, Message: message}})
+}
+
+// ErrorWithDetails writes a JSON error envelope carrying details about what went wrong
+func ErrorWithDetails(w http.ResponseWriter, status int, code, message string, details interface{}) {
+	JSON(w, status, ErrorResponse{Error: ErrorBody{Code: code, Message: message, Details: details}})
+}
+
 func BadRequest(w http.ResponseWriter, message string) {
-	http.Error(w, message, http.StatusBadRequest)
+	Error(w, http.StatusBadRequest, "bad_request", message)
+}
+
+func Unauthorized(w http.ResponseWriter, message string) {
+	Error(w, http.StatusUnauthorized, "unauthorized", message)
+}
+
+func Forbidden(w http.ResponseWriter, message string) {
+	Error(w, http.StatusForbidden, "forbidden", message)
+}
+
+func NotFound(w http.ResponseWriter, message string) {
+	Error(w, http.StatusNotFound, "not_found", message)
+}
+
+func MethodNotAllowed(w http.ResponseWriter) {
+	Error(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed")
+}
+
+func Conflict(w http.ResponseWriter, message string) {
+	Error(w, http.StatusConflict, "conflict", message)
+}
+
+func RequestTooLarge(w http.ResponseWriter, message string) {
+	Error(w, http.StatusRequestEntityTooLarge, "request_too_large", message)
+}
+
+func Unprocessable(w http.ResponseWriter, message string) {
+	Error(w, http.StatusUnprocessableEntity, "unprocessable", message)
+}
+
+func TooManyRequests(w http.ResponseWriter, message string) {
+	Error(w, http.StatusTooManyRequests, "too_many_requests", message)
+}
+
+func ServiceUnavailable(w http.ResponseWriter, message string) {
+	Error(w, http.StatusServiceUnavailable, "service_unavailable", message)
+}
+
+func InternalServerError(w http.ResponseWriter, message string) {
+	Error(w, http.StatusInternalServerError, "internal_error", message)
 }
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// HandleAuditLogs handles GET /audit
+func (h *AuditHandler) HandleAuditLogs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.AuditLogFilter{
+		Action:    query.Get("action"),
+		TableName: query.Get("entity_type"),
+	}
+
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			api.BadRequest(w, "Invalid user_id")
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	// Dates are business days, the end date is inclusive
+	if startStr := query.Get("start_date"); startStr != "" {
+		start, err := time.ParseInLocation("2006-01-02", startStr, time.Local)
+		if err != nil {
+			api.BadRequest(w, "Invalid start_date, expected YYYY-MM-DD")
+			return
+		}
+		filter.Start = &start
+	}
+	if endStr := query.Get("end_date"); endStr != "" {
+		end, err := time.ParseInLocation("2006-01-02", endStr, time.Local)
+		if err != nil {
+			api.BadRequest(w, "Invalid end_date, expected YYYY-MM-DD")
+			return
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.End = &end
+	}
+
+	var err error
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if filter.Limit, err = strconv.Atoi(limitStr); err != nil {
+			api.BadRequest(w, "Invalid limit")
+			return
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if filter.Offset, err = strconv.Atoi(offsetStr); err != nil {
+			api.BadRequest(w, "Invalid offset")
+			return
+		}
+	}
+
+	page, err := h.auditService.ListAuditLogs(r.Context(), filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPagination):
+			api.BadRequest(w, err.Error())
+		case errors.Is(err, service.ErrInvalidDateRange):
+			api.BadRequest(w, "end_date must not be before start_date")
+		default:
+			log.Printf("Failed to list audit logs: %v", err)
+			api.InternalServerError(w, "Failed to list audit logs")
+		}
+		return
+	}
+
+	api.JSON(w, http.StatusOK, page)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/middleware"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+// authorFields name who created and last edited a record, which only managers are shown
+var authorFields = []string{"created_by", "updated_by"}
+
+// requireManager restricts a handler to admins and managers
+var requireManager = middleware.RequireRole(models.RoleAdmin, models.RoleManager)
+
+// currentUserID returns the authenticated user's ID from the request context
+func currentUserID(r *http.Request) (uuid.UUID, bool) {
+	idStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// writeJSON writes v like api.JSON, leaving out the author fields unless the caller is an
+// admin or manager
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	if role, _ := middleware.GetUserRole(r.Context()); role == models.RoleAdmin || role == models.RoleManager {
+		api.JSON(w, status, v)
+		return
+	}
+	api.JSON(w, status, withoutAuthors(v))
+}
+
+// withoutAuthors returns the JSON form of v with the author fields removed at every level,
+// for payloads that reach staff other than managers
+func withoutAuthors(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Failed to encode payload: %v", err)
+		return v
+	}
+
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep numbers exactly as encoded
+	if err := decoder.Decode(&doc); err != nil {
+		log.Printf("Failed to decode payload: %v", err)
+		return v
+	}
+
+	stripAuthors(doc)
+	return doc
+}
+
+// stripAuthors deletes the author fields from every object in a decoded JSON document
+func stripAuthors(doc interface{}) {
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		for _, field := range authorFields {
+			delete(node, field)
+		}
+		for _, child := range node {
+			stripAuthors(child)
+		}
+	case []interface{}:
+		for _, child := range node {
+			stripAuthors(child)
+		}
+	}
+}
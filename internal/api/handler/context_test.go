@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/pizza-nz/restaurant-service/internal/middleware"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+func TestWriteJSONAuthors(t *testing.T) {
+	author := uuid.MustParse("0b7e4f4e-6a0c-4c52-9d0b-5b2f1f7e2a11")
+	station := models.Station{
+		Name:      "Grill",
+		CreatedBy: &author,
+		UpdatedBy: &author,
+		Printer:   &models.Printer{Name: "Pass", CreatedBy: &author},
+	}
+
+	tests := []struct {
+		role        string
+		wantAuthors bool
+	}{
+		{string(models.RoleManager), true},
+		{string(models.RoleAdmin), true},
+		{string(models.RoleKitchen), false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/stations", nil)
+			if tt.role != "" {
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserRoleKey, tt.role))
+			}
+			rec := httptest.NewRecorder()
+			writeJSON(rec, req, http.StatusOK, []models.Station{station})
+
+			body := rec.Body.String()
+			if got := strings.Contains(body, author.String()); got != tt.wantAuthors {
+				t.Errorf("authors in body = %v, want %v: %s", got, tt.wantAuthors, body)
+			}
+			if !strings.Contains(body, `"name":"Pass"`) {
+				t.Errorf("nested printer missing: %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+// IntegrationHandler handles webhooks from third-party ordering platforms
+type IntegrationHandler struct {
+	integrationService *service.IntegrationService
+	hub                websockets.Broadcaster
+}
+
+// NewIntegrationHandler creates a new integration handler
+func NewIntegrationHandler(integrationService *service.IntegrationService, hub websockets.Broadcaster) *IntegrationHandler {
+	if hub == nil {
+		hub = websockets.Discard
+	}
+
+	return &IntegrationHandler{
+		integrationService: integrationService,
+		hub:                hub,
+	}
+}
+
+// HandleInboundOrder handles POST /integrations/{provider}/orders. Instead of a user token the
+// platform signs "<timestamp>.<body>" with an HMAC-SHA256 in the X-Signature header, the Unix
+// timestamp going in X-Signature-Timestamp.
+func (h *IntegrationHandler) HandleInboundOrder(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	body, ok := api.ReadBody(w, r)
+	if !ok {
+		return
+	}
+
+	order, created, err := h.integrationService.ReceiveOrder(r.Context(), r.PathValue("provider"), body, r.Header.Get("X-Signature-Timestamp"), r.Header.Get("X-Signature"))
+	if err != nil {
+		var itemsErr *service.OrderItemsError
+		switch {
+		case errors.Is(err, service.ErrUnknownProvider):
+			api.NotFound(w, "Unknown provider")
+		case errors.Is(err, service.ErrInvalidSignature):
+			api.Unauthorized(w, "Invalid signature")
+		case errors.Is(err, service.ErrInvalidInboundOrder), errors.Is(err, service.ErrInvalidCustomer):
+			api.BadRequest(w, err.Error())
+		case errors.As(err, &itemsErr):
+			api.ErrorWithDetails(w, http.StatusUnprocessableEntity, "unprocessable", "Order has invalid items", itemsErr.Problems)
+		case errors.Is(err, service.ErrMissingRequiredModifier), errors.Is(err, service.ErrTooManyModifierOptions):
+			api.Unprocessable(w, err.Error())
+		default:
+			log.Printf("Failed to receive inbound order: %v", err)
+			api.InternalServerError(w, "Failed to receive order")
+		}
+		return
+	}
+
+	// A repeated delivery gets the order already created
+	if !created {
+		writeJSON(w, r, http.StatusOK, order)
+		return
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypeOrderNew, withoutAuthors(order)); err != nil {
+		log.Printf("Failed to broadcast new order: %v", err)
+	}
+
+	writeJSON(w, r, http.StatusCreated, order)
+}
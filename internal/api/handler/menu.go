@@ -0,0 +1,716 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+// MenuHandler handles menu HTTP requests
+type MenuHandler struct {
+	menuService *service.MenuService
+	hub         websockets.Broadcaster
+}
+
+// NewMenuHandler creates a new menu handler
+func NewMenuHandler(menuService *service.MenuService, hub websockets.Broadcaster) *MenuHandler {
+	if hub == nil {
+		hub = websockets.Discard
+	}
+
+	return &MenuHandler{
+		menuService: menuService,
+		hub:         hub,
+	}
+}
+
+// HandleCategories handles GET and POST /menu/categories
+func (h *MenuHandler) HandleCategories(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.listCategories(w, r)
+	case http.MethodPost:
+		requireManager(http.HandlerFunc(h.createCategory)).ServeHTTP(w, r)
+	}
+}
+
+// HandleCategory handles GET and PUT /menu/categories/{id}
+func (h *MenuHandler) HandleCategory(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.getCategory(w, r)
+	case http.MethodPut:
+		requireManager(http.HandlerFunc(h.updateCategory)).ServeHTTP(w, r)
+	}
+}
+
+// listCategories lists the menu categories
+func (h *MenuHandler) listCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.menuService.GetCategories(r.Context())
+	if err != nil {
+		log.Printf("Failed to list categories: %v", err)
+		api.InternalServerError(w, "Failed to list categories")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, categories)
+}
+
+// getCategory gets a menu category by ID
+func (h *MenuHandler) getCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid category ID")
+		return
+	}
+
+	category, err := h.menuService.GetCategory(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Category not found")
+			return
+		}
+		log.Printf("Failed to get category: %v", err)
+		api.InternalServerError(w, "Failed to get category")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, category)
+}
+
+// createCategory creates a menu category, optionally with the station its items default to
+func (h *MenuHandler) createCategory(w http.ResponseWriter, r *http.Request) {
+	var req models.MenuCategoryRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	category, err := h.menuService.CreateCategory(r.Context(), req)
+	if err != nil {
+		writeCategoryError(w, err, "create")
+		return
+	}
+
+	h.broadcastMenuUpdate("category_created", category.ID)
+
+	writeJSON(w, r, http.StatusCreated, category)
+}
+
+// updateCategory replaces a menu category's details, including its default station
+func (h *MenuHandler) updateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid category ID")
+		return
+	}
+
+	var req models.MenuCategoryRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	category, err := h.menuService.UpdateCategory(r.Context(), id, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Category not found")
+			return
+		}
+		writeCategoryError(w, err, "update")
+		return
+	}
+
+	h.broadcastMenuUpdate("category_updated", category.ID)
+
+	writeJSON(w, r, http.StatusOK, category)
+}
+
+// HandleMergeCategory handles POST /menu/categories/{id}/merge
+func (h *MenuHandler) HandleMergeCategory(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	sourceID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid category ID")
+		return
+	}
+
+	var req models.MenuCategoryMergeRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	if sourceID == req.TargetID {
+		api.BadRequest(w, "Cannot merge a category into itself")
+		return
+	}
+
+	category, err := h.menuService.MergeCategories(r.Context(), sourceID, req.TargetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Category not found")
+			return
+		}
+		log.Printf("Failed to merge categories: %v", err)
+		api.InternalServerError(w, "Failed to merge categories")
+		return
+	}
+
+	h.broadcastMenuUpdate("categories_merged", category.ID)
+
+	writeJSON(w, r, http.StatusOK, category)
+}
+
+// HandlePriceAdjust handles POST /menu/price-adjust, raising or lowering prices by a percentage
+func (h *MenuHandler) HandlePriceAdjust(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.MenuPriceAdjustRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	result, err := h.menuService.AdjustPrices(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPriceAdjustment):
+			api.BadRequest(w, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Category not found")
+		default:
+			log.Printf("Failed to adjust prices: %v", err)
+			api.InternalServerError(w, "Failed to adjust prices")
+		}
+		return
+	}
+
+	h.broadcastMenuUpdate("prices_adjusted", result.CategoryID)
+
+	writeJSON(w, r, http.StatusOK, result)
+}
+
+// HandleBulkDeleteItems handles POST /menu/items/bulk-delete
+func (h *MenuHandler) HandleBulkDeleteItems(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req models.MenuItemBulkDeleteRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		api.BadRequest(w, "ids is required")
+		return
+	}
+
+	results, err := h.menuService.BulkDeleteItems(r.Context(), req.IDs)
+	if err != nil {
+		log.Printf("Failed to bulk delete menu items: %v", err)
+		api.InternalServerError(w, "Failed to delete menu items")
+		return
+	}
+
+	// Send a single update for the whole batch
+	deleted := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Deleted {
+			deleted = append(deleted, result.ID.String())
+		}
+	}
+	if len(deleted) > 0 {
+		h.broadcastMenuUpdate("items_deleted", deleted)
+	}
+
+	writeJSON(w, r, http.StatusOK, results)
+}
+
+// HandleMenuItems handles GET and POST /menu/items
+func (h *MenuHandler) HandleMenuItems(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.listItems(w, r)
+	case http.MethodPost:
+		requireManager(http.HandlerFunc(h.createItem)).ServeHTTP(w, r)
+	}
+}
+
+// HandleMenuItem handles GET and PUT /menu/items/{id}
+func (h *MenuHandler) HandleMenuItem(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodPatch) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.getItem(w, r)
+	case http.MethodPut:
+		requireManager(http.HandlerFunc(h.updateItem)).ServeHTTP(w, r)
+	case http.MethodPatch:
+		requireManager(http.HandlerFunc(h.patchItem)).ServeHTTP(w, r)
+	}
+}
+
+// listItems lists menu items, filtered by optional category_id, tag and featured query parameters.
+// sort=featured lists featured items first.
+func (h *MenuHandler) listItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.MenuItemFilter{Tag: query.Get("tag")}
+
+	if raw := query.Get("category_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			api.BadRequest(w, "Invalid category ID")
+			return
+		}
+		filter.CategoryID = &id
+	}
+
+	if raw := query.Get("featured"); raw != "" {
+		featured, err := strconv.ParseBool(raw)
+		if err != nil {
+			api.BadRequest(w, "Invalid featured, expected true or false")
+			return
+		}
+		filter.Featured = &featured
+	}
+
+	switch query.Get("sort") {
+	case "", "name":
+	case "featured":
+		filter.FeaturedFirst = true
+	default:
+		api.BadRequest(w, "sort must be one of: name, featured")
+		return
+	}
+
+	items, err := h.menuService.GetItems(r.Context(), filter)
+	if err != nil {
+		log.Printf("Failed to list menu items: %v", err)
+		api.InternalServerError(w, "Failed to list menu items")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, items)
+}
+
+// getItem returns a single menu item
+func (h *MenuHandler) getItem(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid menu item ID")
+		return
+	}
+
+	item, err := h.menuService.GetItem(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Menu item not found")
+			return
+		}
+		log.Printf("Failed to get menu item: %v", err)
+		api.InternalServerError(w, "Failed to get menu item")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// createItem creates a menu item
+func (h *MenuHandler) createItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.MenuItemRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	item, err := h.menuService.CreateItem(r.Context(), userID, req)
+	if err != nil {
+		writeMenuItemError(w, err, "create")
+		return
+	}
+
+	h.broadcastMenuUpdate("item_created", item.ID)
+
+	writeJSON(w, r, http.StatusCreated, item)
+}
+
+// updateItem replaces a menu item's details, modifiers, routing and tags
+func (h *MenuHandler) updateItem(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid menu item ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.MenuItemRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	item, err := h.menuService.UpdateItem(r.Context(), id, userID, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Menu item not found")
+			return
+		}
+		writeMenuItemError(w, err, "update")
+		return
+	}
+
+	h.broadcastMenuUpdate("item_updated", item.ID)
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// patchItem updates only the fields present in the request body
+func (h *MenuHandler) patchItem(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid menu item ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var patch models.MenuItemPatchRequest
+	if !api.DecodeJSON(w, r, &patch) {
+		return
+	}
+
+	item, err := h.menuService.PatchItem(r.Context(), id, userID, patch)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Menu item not found")
+			return
+		}
+		writeMenuItemError(w, err, "update")
+		return
+	}
+
+	h.broadcastMenuUpdate("item_updated", item.ID)
+	if patch.Available != nil {
+		h.broadcastItemAvailability(item)
+	}
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// HandleItemAvailability handles PUT /menu/items/{id}/availability, marking an item
+// available or 86'd. Any signed-in user can do this so the kitchen can 86 an item mid-service.
+func (h *MenuHandler) HandleItemAvailability(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid menu item ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.MenuItemAvailabilityRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+	if req.Available == nil {
+		api.BadRequest(w, "available is required")
+		return
+	}
+
+	item, err := h.menuService.SetItemAvailability(r.Context(), id, userID, *req.Available)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Menu item not found")
+			return
+		}
+		writeMenuItemError(w, err, "update")
+		return
+	}
+
+	h.broadcastMenuUpdate("item_updated", item.ID)
+	h.broadcastItemAvailability(item)
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// HandlePriceHistory handles GET /menu/items/{id}/price-history
+func (h *MenuHandler) HandlePriceHistory(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid menu item ID")
+		return
+	}
+
+	history, err := h.menuService.GetPriceHistory(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Menu item not found")
+			return
+		}
+		log.Printf("Failed to get price history: %v", err)
+		api.InternalServerError(w, "Failed to get price history")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, history)
+}
+
+// HandleModifiers handles GET /modifiers, optionally filtered with q (name) and multiple, and
+// POST /modifiers
+func (h *MenuHandler) HandleModifiers(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		requireManager(http.HandlerFunc(h.createModifier)).ServeHTTP(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.ModifierFilter{Query: query.Get("q")}
+
+	var err error
+	if multipleStr := query.Get("multiple"); multipleStr != "" {
+		multiple, err := strconv.ParseBool(multipleStr)
+		if err != nil {
+			api.BadRequest(w, "multiple must be true or false")
+			return
+		}
+		filter.Multiple = &multiple
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if filter.Limit, err = strconv.Atoi(limitStr); err != nil {
+			api.BadRequest(w, "Invalid limit")
+			return
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if filter.Offset, err = strconv.Atoi(offsetStr); err != nil {
+			api.BadRequest(w, "Invalid offset")
+			return
+		}
+	}
+
+	page, err := h.menuService.SearchModifiers(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPagination) {
+			api.BadRequest(w, err.Error())
+			return
+		}
+		log.Printf("Failed to list modifiers: %v", err)
+		api.InternalServerError(w, "Failed to list modifiers")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, page)
+}
+
+// HandleModifierUsage handles GET /modifiers/{id}/usage, the menu items a modifier is attached to
+func (h *MenuHandler) HandleModifierUsage(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid modifier ID")
+		return
+	}
+
+	usage, err := h.menuService.GetModifierUsage(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Modifier not found")
+			return
+		}
+		log.Printf("Failed to get modifier usage: %v", err)
+		api.InternalServerError(w, "Failed to get modifier usage")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, usage)
+}
+
+// createModifier creates a modifier group with its options and selection limits
+func (h *MenuHandler) createModifier(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.ModifierRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	modifier, err := h.menuService.CreateModifier(r.Context(), userID, req)
+	if err != nil {
+		writeModifierError(w, err, "create")
+		return
+	}
+
+	h.broadcastMenuUpdate("modifier_created", modifier.ID)
+
+	writeJSON(w, r, http.StatusCreated, modifier)
+}
+
+// HandleModifier handles PUT /modifiers/{id}, replacing its options and selection limits
+func (h *MenuHandler) HandleModifier(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid modifier ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.ModifierRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	modifier, err := h.menuService.UpdateModifier(r.Context(), id, userID, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Modifier not found")
+			return
+		}
+		writeModifierError(w, err, "update")
+		return
+	}
+
+	h.broadcastMenuUpdate("modifier_updated", modifier.ID)
+
+	writeJSON(w, r, http.StatusOK, modifier)
+}
+
+// writeModifierError maps a modifier create or update error to a response
+func writeModifierError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidModifier):
+		api.BadRequest(w, err.Error())
+	case errors.Is(err, service.ErrTooManyOptions):
+		api.Unprocessable(w, err.Error())
+	default:
+		log.Printf("Failed to %s modifier: %v", action, err)
+		api.InternalServerError(w, "Failed to "+action+" modifier")
+	}
+}
+
+// writeCategoryError maps a category create or update error to a response
+func writeCategoryError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCategory):
+		api.BadRequest(w, err.Error())
+	default:
+		log.Printf("Failed to %s category: %v", action, err)
+		api.InternalServerError(w, "Failed to "+action+" category")
+	}
+}
+
+// writeMenuItemError maps menu item service errors to responses
+func writeMenuItemError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidMenuItem):
+		api.BadRequest(w, err.Error())
+	case errors.Is(err, service.ErrStationInactive):
+		api.Unprocessable(w, "Station is inactive")
+	case errors.Is(err, repository.ErrDuplicatePLU):
+		api.Conflict(w, err.Error())
+	default:
+		log.Printf("Failed to %s menu item: %v", action, err)
+		api.InternalServerError(w, "Failed to "+action+" menu item")
+	}
+}
+
+// broadcastMenuUpdate notifies connected clients that the menu has changed
+func (h *MenuHandler) broadcastMenuUpdate(updateType string, id interface{}) {
+	data := map[string]interface{}{
+		"update_type": updateType,
+		"id":          id,
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypeMenuUpdate, data); err != nil {
+		log.Printf("Failed to broadcast menu update: %v", err)
+	}
+}
+
+// broadcastItemAvailability tells POS screens an item's new availability so they can
+// update its button without refetching the menu
+func (h *MenuHandler) broadcastItemAvailability(item *models.MenuItem) {
+	data := models.ItemAvailability{
+		ID:        item.ID,
+		Name:      item.Name,
+		Available: item.Available,
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypeItemAvailability, data); err != nil {
+		log.Printf("Failed to broadcast item availability: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+type broadcast struct {
+	msgType websockets.MessageType
+	data    interface{}
+}
+
+// recordingBroadcaster keeps every message instead of sending it
+type recordingBroadcaster struct {
+	sent []broadcast
+}
+
+func (b *recordingBroadcaster) BroadcastMessage(msgType websockets.MessageType, data interface{}) error {
+	b.sent = append(b.sent, broadcast{msgType, data})
+	return nil
+}
+
+func TestMenuHandlerBroadcaster(t *testing.T) {
+	id := uuid.New()
+
+	t.Run("fake", func(t *testing.T) {
+		hub := &recordingBroadcaster{}
+		NewMenuHandler(nil, hub).broadcastMenuUpdate("item_created", id)
+
+		if len(hub.sent) != 1 {
+			t.Fatalf("sent %d messages, want 1", len(hub.sent))
+		}
+		if hub.sent[0].msgType != websockets.TypeMenuUpdate {
+			t.Errorf("type = %s, want %s", hub.sent[0].msgType, websockets.TypeMenuUpdate)
+		}
+		data, _ := hub.sent[0].data.(map[string]interface{})
+		if data["update_type"] != "item_created" || data["id"] != id {
+			t.Errorf("data = %v", hub.sent[0].data)
+		}
+	})
+
+	// Neither an untyped nil nor a nil *Hub may panic when a handler broadcasts
+	for name, hub := range map[string]websockets.Broadcaster{
+		"nil":     nil,
+		"nil hub": (*websockets.Hub)(nil),
+		"discard": websockets.Discard,
+	} {
+		t.Run(name, func(t *testing.T) {
+			NewMenuHandler(nil, hub).broadcastMenuUpdate("item_created", id)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pizza-nz/restaurant-service/internal/api"
+)
+
+// allowMethods checks the request method against the methods a handler supports.
+// HEAD is accepted wherever GET is, net/http discards the body for it. OPTIONS is
+// answered with the Allow header and any other method gets a 405, both return false.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method || (r.Method == http.MethodHead && method == http.MethodGet) {
+			return true
+		}
+	}
+
+	w.Header().Set("Allow", allowHeader(methods))
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return false
+	}
+
+	api.MethodNotAllowed(w)
+	return false
+}
+
+// allowHeader lists methods for an Allow header, adding HEAD alongside GET and OPTIONS
+func allowHeader(methods []string) string {
+	allowed := make([]string, 0, len(methods)+2)
+	for _, method := range methods {
+		allowed = append(allowed, method)
+		if method == http.MethodGet {
+			allowed = append(allowed, http.MethodHead)
+		}
+	}
+	allowed = append(allowed, http.MethodOptions)
+
+	return strings.Join(allowed, ", ")
+}
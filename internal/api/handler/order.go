@@ -0,0 +1,599 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+// OrderHandler handles order HTTP requests
+type OrderHandler struct {
+	orderService *service.OrderService
+	hub          websockets.Broadcaster
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(orderService *service.OrderService, hub websockets.Broadcaster) *OrderHandler {
+	if hub == nil {
+		hub = websockets.Discard
+	}
+
+	return &OrderHandler{
+		orderService: orderService,
+		hub:          hub,
+	}
+}
+
+// HandleOrders handles GET and POST /orders
+func (h *OrderHandler) HandleOrders(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.searchOrders(w, r)
+	case http.MethodPost:
+		h.createOrder(w, r)
+	}
+}
+
+// searchOrders finds the last week's orders by order_number_suffix, for the POS search bar.
+// Orders whose number ends with the fragment come first, then ones that only contain it.
+func (h *OrderHandler) searchOrders(w http.ResponseWriter, r *http.Request) {
+	fragment := strings.TrimSpace(r.URL.Query().Get("order_number_suffix"))
+	if fragment == "" {
+		api.BadRequest(w, "order_number_suffix is required")
+		return
+	}
+
+	orders, err := h.orderService.SearchByOrderNumber(r.Context(), fragment)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrderNumberSearch) {
+			api.BadRequest(w, err.Error())
+			return
+		}
+		log.Printf("Failed to search orders: %v", err)
+		api.InternalServerError(w, "Failed to search orders")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, orders)
+}
+
+// HandleOpenOrders handles GET /orders/open, listing every order that isn't completed or cancelled
+func (h *OrderHandler) HandleOpenOrders(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.OpenOrderFilter{Sort: models.OpenOrderSort(query.Get("sort"))}
+	switch filter.Sort {
+	case "", models.OpenOrderSortAge, models.OpenOrderSortNewest:
+	default:
+		api.BadRequest(w, "sort must be one of: age, newest")
+		return
+	}
+
+	var err error
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if filter.Limit, err = strconv.Atoi(limitStr); err != nil {
+			api.BadRequest(w, "Invalid limit")
+			return
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if filter.Offset, err = strconv.Atoi(offsetStr); err != nil {
+			api.BadRequest(w, "Invalid offset")
+			return
+		}
+	}
+
+	page, err := h.orderService.ListOpenOrders(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPagination) {
+			api.BadRequest(w, err.Error())
+			return
+		}
+		log.Printf("Failed to list open orders: %v", err)
+		api.InternalServerError(w, "Failed to list open orders")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, page)
+}
+
+// HandleOrderHistory handles GET /orders/history. start_date and end_date take a
+// date or an RFC3339 datetime, and tz sets the zone that dates are read in.
+func (h *OrderHandler) HandleOrderHistory(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	start, end, ok := parseTimeRange(w, r)
+	if !ok {
+		return
+	}
+
+	orders, err := h.orderService.GetOrderHistory(r.Context(), start, end)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			api.BadRequest(w, "end_date must not be before start_date")
+			return
+		}
+		log.Printf("Failed to get order history: %v", err)
+		api.InternalServerError(w, "Failed to get order history")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, orders)
+}
+
+// HandleOrderNotes handles PUT /orders/{id}/notes
+func (h *OrderHandler) HandleOrderNotes(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	var req models.OrderNotesRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	order, err := h.orderService.UpdateNotes(r.Context(), id, userID, req.Notes)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotOpen) {
+			api.Conflict(w, "Order is not open")
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Order not found")
+			return
+		}
+		log.Printf("Failed to update order notes: %v", err)
+		api.InternalServerError(w, "Failed to update order notes")
+		return
+	}
+
+	h.broadcastOrderUpdate(models.OrderUpdate{UpdateType: "notes", ID: order.ID, Status: order.Status})
+
+	writeJSON(w, r, http.StatusOK, order)
+}
+
+// HandleOrderStatus handles PUT /orders/{id}/status
+func (h *OrderHandler) HandleOrderStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	var req models.OrderStatusRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	order, err := h.orderService.UpdateStatus(r.Context(), id, req.Status)
+	if err != nil {
+		writeStatusError(w, err, "Order not found")
+		return
+	}
+
+	h.broadcastOrderUpdate(models.OrderUpdate{UpdateType: "status", ID: order.ID, Status: order.Status})
+
+	writeJSON(w, r, http.StatusOK, order)
+}
+
+// HandleReopenOrder handles POST /orders/{id}/reopen for orders completed by mistake
+func (h *OrderHandler) HandleReopenOrder(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.OrderReopenRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		api.BadRequest(w, "A reason is required")
+		return
+	}
+
+	order, err := h.orderService.ReopenOrder(r.Context(), id, userID, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Order not found")
+		case errors.Is(err, service.ErrOrderNotCompleted), errors.Is(err, service.ErrReopenWindowExpired),
+			errors.Is(err, repository.ErrOrderStatusChanged):
+			api.Conflict(w, err.Error())
+		default:
+			log.Printf("Failed to reopen order: %v", err)
+			api.InternalServerError(w, "Failed to reopen order")
+		}
+		return
+	}
+
+	h.broadcastOrderUpdate(models.OrderUpdate{UpdateType: "reopen", ID: order.ID, Status: order.Status})
+
+	writeJSON(w, r, http.StatusOK, order)
+}
+
+// HandleOrderRush handles POST /orders/{id}/rush to rush an order and DELETE to clear it
+func (h *OrderHandler) HandleOrderRush(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost, http.MethodDelete) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	order, err := h.orderService.SetRush(r.Context(), id, r.Method == http.MethodPost)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Order not found")
+		case errors.Is(err, service.ErrOrderNotOpen):
+			api.Conflict(w, "Order is not open")
+		default:
+			log.Printf("Failed to set order rush: %v", err)
+			api.InternalServerError(w, "Failed to set order rush")
+		}
+		return
+	}
+
+	// Stations re-sort their feeds on this update
+	h.broadcastOrderUpdate(models.OrderUpdate{UpdateType: "rush", ID: order.ID, Status: order.Status, IsRush: &order.IsRush})
+
+	writeJSON(w, r, http.StatusOK, order)
+}
+
+// HandleOrderItemRush handles POST and DELETE /orders/{id}/items/{itemId}/rush
+func (h *OrderHandler) HandleOrderItemRush(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost, http.MethodDelete) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	itemID, err := uuid.Parse(r.PathValue("itemId"))
+	if err != nil {
+		api.BadRequest(w, "Invalid item ID")
+		return
+	}
+
+	item, err := h.orderService.SetItemRush(r.Context(), id, itemID, r.Method == http.MethodPost)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Order item not found")
+		case errors.Is(err, service.ErrOrderNotOpen):
+			api.Conflict(w, "Order is not open")
+		case errors.Is(err, service.ErrItemNotWaiting):
+			api.Conflict(w, err.Error())
+		default:
+			log.Printf("Failed to set order item rush: %v", err)
+			api.InternalServerError(w, "Failed to set order item rush")
+		}
+		return
+	}
+
+	// The item's station re-sorts its feed on this update
+	h.broadcastOrderUpdate(models.OrderUpdate{
+		UpdateType: "item_rush",
+		ID:         item.OrderID,
+		IsRush:     &item.IsRush,
+		ItemID:     &item.ID,
+		StationID:  &item.StationID,
+	})
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// HandleKitchenActive handles GET /kitchen/active, every order being cooked grouped by station
+func (h *OrderHandler) HandleKitchenActive(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	orders, err := h.orderService.GetKitchenActive(r.Context())
+	if err != nil {
+		log.Printf("Failed to get active kitchen orders: %v", err)
+		api.InternalServerError(w, "Failed to get active kitchen orders")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, orders)
+}
+
+// HandleOrderHold handles PUT /orders/{id}/hold, keeping an order's items off the stations
+func (h *OrderHandler) HandleOrderHold(w http.ResponseWriter, r *http.Request) {
+	h.setHold(w, r, true)
+}
+
+// HandleOrderRelease handles PUT /orders/{id}/release, firing a held order to the stations
+func (h *OrderHandler) HandleOrderRelease(w http.ResponseWriter, r *http.Request) {
+	h.setHold(w, r, false)
+}
+
+// setHold holds or releases an order and broadcasts the change
+func (h *OrderHandler) setHold(w http.ResponseWriter, r *http.Request, hold bool) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	order, err := h.orderService.SetHold(r.Context(), id, hold)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Order not found")
+		case errors.Is(err, service.ErrOrderNotOpen):
+			api.Conflict(w, "Order is not open")
+		case errors.Is(err, service.ErrOrderOnHold), errors.Is(err, service.ErrOrderNotOnHold):
+			api.Conflict(w, err.Error())
+		default:
+			log.Printf("Failed to set order hold: %v", err)
+			api.InternalServerError(w, "Failed to set order hold")
+		}
+		return
+	}
+
+	// Stations add or drop the order's items on this update
+	updateType := "hold"
+	if !hold {
+		updateType = "release"
+	}
+	held := order.IsHeld()
+	h.broadcastOrderUpdate(models.OrderUpdate{UpdateType: updateType, ID: order.ID, Status: order.Status, IsHeld: &held})
+
+	writeJSON(w, r, http.StatusOK, order)
+}
+
+// HandleOrderItemStatus handles PUT /orders/{id}/items/{itemId}/status
+func (h *OrderHandler) HandleOrderItemStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	itemID, err := uuid.Parse(r.PathValue("itemId"))
+	if err != nil {
+		api.BadRequest(w, "Invalid item ID")
+		return
+	}
+
+	var req models.OrderItemStatusRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	item, err := h.orderService.UpdateItemStatus(r.Context(), id, itemID, userID, req)
+	if err != nil {
+		writeStatusError(w, err, "Order item not found")
+		return
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypeItemUpdate, item); err != nil {
+		log.Printf("Failed to broadcast item update: %v", err)
+	}
+	h.broadcastOrderUpdate(models.OrderUpdate{
+		UpdateType: "item_status",
+		ID:         item.OrderID,
+		ItemID:     &item.ID,
+		ItemStatus: item.Status,
+		StationID:  &item.StationID,
+	})
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// writeStatusError maps a status change error to a response
+func writeStatusError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidStatus):
+		api.BadRequest(w, err.Error())
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		api.Unprocessable(w, err.Error())
+	case errors.Is(err, repository.ErrNotFound):
+		api.NotFound(w, notFound)
+	case errors.Is(err, repository.ErrOrderStatusChanged):
+		api.Conflict(w, err.Error())
+	default:
+		log.Printf("Failed to update status: %v", err)
+		api.InternalServerError(w, "Failed to update status")
+	}
+}
+
+// HandleRecalculateTotal handles POST /orders/{id}/recalculate
+func (h *OrderHandler) HandleRecalculateTotal(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	result, err := h.orderService.RecalculateTotal(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Order not found")
+			return
+		}
+		log.Printf("Failed to recalculate order total: %v", err)
+		api.InternalServerError(w, "Failed to recalculate order total")
+		return
+	}
+
+	if result.Changed {
+		h.broadcastOrderUpdate(models.OrderUpdate{UpdateType: "total_recalculated", ID: id, Total: &result.Total})
+	}
+
+	writeJSON(w, r, http.StatusOK, result)
+}
+
+// HandleOrderTimeline handles GET /orders/{id}/timeline
+func (h *OrderHandler) HandleOrderTimeline(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid order ID")
+		return
+	}
+
+	events, err := h.orderService.GetTimeline(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Order not found")
+			return
+		}
+		log.Printf("Failed to get order timeline: %v", err)
+		api.InternalServerError(w, "Failed to get order timeline")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, events)
+}
+
+// createOrder creates a new order for the authenticated user
+func (h *OrderHandler) createOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.OrderRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.Items) == 0 {
+		api.BadRequest(w, "Order must contain at least one item")
+		return
+	}
+
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			api.BadRequest(w, "Quantity must be greater than zero")
+			return
+		}
+		if item.Seat != nil && *item.Seat < 1 {
+			api.BadRequest(w, "Seat must be a positive number")
+			return
+		}
+	}
+
+	order, err := h.orderService.CreateOrder(r.Context(), userID, req)
+	if err != nil {
+		var itemsErr *service.OrderItemsError
+		if errors.As(err, &itemsErr) {
+			api.ErrorWithDetails(w, http.StatusUnprocessableEntity, "unprocessable", "Order has invalid items", itemsErr.Problems)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCustomer) {
+			api.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrMissingRequiredModifier) || errors.Is(err, service.ErrTooManyModifierOptions) ||
+			errors.Is(err, repository.ErrInvalidQuantity) || errors.Is(err, repository.ErrNoStationConfigured) {
+			api.Unprocessable(w, err.Error())
+			return
+		}
+		// The order itself is new, so a missing record is one the request referred to
+		if errors.Is(err, repository.ErrNotFound) {
+			api.Unprocessable(w, "Menu item or modifier option not found")
+			return
+		}
+		log.Printf("Failed to create order: %v", err)
+		api.InternalServerError(w, "Failed to create order")
+		return
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypeOrderNew, withoutAuthors(order)); err != nil {
+		log.Printf("Failed to broadcast new order: %v", err)
+	}
+
+	writeJSON(w, r, http.StatusCreated, order)
+}
+
+// broadcastOrderUpdate notifies connected clients that an order has changed
+func (h *OrderHandler) broadcastOrderUpdate(update models.OrderUpdate) {
+	if err := h.hub.BroadcastMessage(websockets.TypeOrderUpdate, update); err != nil {
+		log.Printf("Failed to broadcast order update: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+// PrinterHandler handles printer HTTP requests
+type PrinterHandler struct {
+	printerService *service.PrinterService
+	hub            websockets.Broadcaster
+}
+
+// NewPrinterHandler creates a new printer handler
+func NewPrinterHandler(printerService *service.PrinterService, hub websockets.Broadcaster) *PrinterHandler {
+	if hub == nil {
+		hub = websockets.Discard
+	}
+
+	return &PrinterHandler{
+		printerService: printerService,
+		hub:            hub,
+	}
+}
+
+// HandlePrinters handles POST /printers
+func (h *PrinterHandler) HandlePrinters(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.PrinterRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	printer, err := h.printerService.CreatePrinter(r.Context(), userID, req)
+	if err != nil {
+		writePrinterError(w, err, "create")
+		return
+	}
+
+	h.broadcastPrinterUpdate("created", printer.ID)
+
+	writeJSON(w, r, http.StatusCreated, printer)
+}
+
+// HandlePrinter handles PUT /printers/{id}
+func (h *PrinterHandler) HandlePrinter(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid printer ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.PrinterRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	printer, err := h.printerService.UpdatePrinter(r.Context(), id, userID, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Printer not found")
+			return
+		}
+		writePrinterError(w, err, "update")
+		return
+	}
+
+	h.broadcastPrinterUpdate("updated", printer.ID)
+
+	writeJSON(w, r, http.StatusOK, printer)
+}
+
+// writePrinterError maps a printer create or update error to a response
+func writePrinterError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPrinter), errors.Is(err, service.ErrInvalidPrinterPurpose):
+		api.BadRequest(w, err.Error())
+	case errors.Is(err, repository.ErrDefaultPrinterConflict):
+		api.Conflict(w, err.Error())
+	default:
+		log.Printf("Failed to %s printer: %v", action, err)
+		api.InternalServerError(w, "Failed to "+action+" printer")
+	}
+}
+
+// HandleDefaultPrinter handles GET /printers/default?purpose=receipt
+func (h *PrinterHandler) HandleDefaultPrinter(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	purpose := models.PrinterPurpose(r.URL.Query().Get("purpose"))
+	if purpose == "" {
+		purpose = models.PrinterPurposeReceipt
+	}
+
+	printer, err := h.printerService.GetDefaultPrinter(r.Context(), purpose)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPrinterPurpose) {
+			api.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "No default printer for "+string(purpose))
+			return
+		}
+		log.Printf("Failed to get default printer: %v", err)
+		api.InternalServerError(w, "Failed to get default printer")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, printer)
+}
+
+// HandleSetDefaultPrinter handles POST /printers/{id}/set-default, optionally ?purpose=kitchen or label
+func (h *PrinterHandler) HandleSetDefaultPrinter(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid printer ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	purpose := models.PrinterPurpose(r.URL.Query().Get("purpose"))
+
+	printer, err := h.printerService.SetDefaultPrinter(r.Context(), id, userID, purpose)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPrinterPurpose) {
+			api.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrDefaultPrinterConflict) {
+			api.Conflict(w, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Printer not found or inactive")
+			return
+		}
+		log.Printf("Failed to set default printer: %v", err)
+		api.InternalServerError(w, "Failed to set default printer")
+		return
+	}
+
+	h.broadcastPrinterUpdate("default_changed", printer.ID)
+
+	writeJSON(w, r, http.StatusOK, printer)
+}
+
+// broadcastPrinterUpdate notifies connected clients that a printer has changed
+func (h *PrinterHandler) broadcastPrinterUpdate(updateType string, id uuid.UUID) {
+	data := map[string]interface{}{
+		"update_type": updateType,
+		"id":          id,
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypePrinterStatus, data); err != nil {
+		log.Printf("Failed to broadcast printer update: %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+)
+
+// orderExportHeader is the header row of the orders CSV export
+var orderExportHeader = []string{"order_number", "ordered_at", "type", "total", "status"}
+
+// ReportHandler handles report HTTP requests
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// HandlePrepTimes handles GET /reports/prep-times
+func (h *ReportHandler) HandlePrepTimes(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	startDate, endDate, ok := parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetPrepTimes(r.Context(), startDate, endDate)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			api.BadRequest(w, "end_date must not be before start_date")
+			return
+		}
+		log.Printf("Failed to get prep time report: %v", err)
+		api.InternalServerError(w, "Failed to get prep time report")
+		return
+	}
+
+	api.JSON(w, http.StatusOK, report)
+}
+
+// HandleWaste handles GET /reports/waste
+func (h *ReportHandler) HandleWaste(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	startDate, endDate, ok := parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetWaste(r.Context(), startDate, endDate)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			api.BadRequest(w, "end_date must not be before start_date")
+			return
+		}
+		log.Printf("Failed to get waste report: %v", err)
+		api.InternalServerError(w, "Failed to get waste report")
+		return
+	}
+
+	api.JSON(w, http.StatusOK, report)
+}
+
+// HandleOrdersCSV handles GET /reports/orders.csv, one business day's orders for accounting.
+// date defaults to the current business day in the tz location.
+func (h *ReportHandler) HandleOrdersCSV(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	loc, ok := parseLocation(w, r)
+	if !ok {
+		return
+	}
+
+	date := service.BusinessDate(time.Now().In(loc))
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		var err error
+		if date, err = time.ParseInLocation("2006-01-02", dateStr, loc); err != nil {
+			api.BadRequest(w, "Invalid date, expected YYYY-MM-DD")
+			return
+		}
+	}
+
+	// A busy day can outlast the server's write timeout, so allow writing until the
+	// request's own deadline
+	if deadline, ok := r.Context().Deadline(); ok {
+		_ = http.NewResponseController(w).SetWriteDeadline(deadline)
+	}
+
+	writeOrdersCSV(w, "orders-"+date.Format("2006-01-02")+".csv", loc, func(fn func(models.Order) error) error {
+		return h.reportService.ExportOrders(r.Context(), date, fn)
+	})
+}
+
+// writeOrdersCSV streams the orders export produced by export. Nothing is written until the
+// first row has been read, so a failing query still answers 500. Once rows have been sent a
+// failure aborts the response, leaving the client with a broken download rather than a file
+// that looks complete.
+func writeOrdersCSV(w http.ResponseWriter, filename string, loc *time.Location, export func(func(models.Order) error) error) {
+	var cw *csv.Writer
+	start := func() error {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		cw = csv.NewWriter(w)
+		return cw.Write(orderExportHeader)
+	}
+
+	err := export(func(order models.Order) error {
+		if cw == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return cw.Write(orderExportRow(order, loc))
+	})
+	if err != nil && cw == nil {
+		log.Printf("Failed to export orders: %v", err)
+		api.InternalServerError(w, "Failed to export orders")
+		return
+	}
+
+	// A day without orders still gets its header row
+	if err == nil && cw == nil {
+		err = start()
+	}
+	if err == nil {
+		cw.Flush()
+		err = cw.Error()
+	}
+	if err != nil {
+		log.Printf("Failed to export orders: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// orderExportRow formats one order for the orders CSV export, times in loc
+func orderExportRow(order models.Order, loc *time.Location) []string {
+	return []string{
+		order.OrderNumber,
+		order.OrderedAt.In(loc).Format(time.RFC3339),
+		string(order.Source),
+		models.FormatMoney(order.Total),
+		string(order.Status),
+	}
+}
+
+// parseDateRange reads the start_date and end_date query parameters as business dates, both
+// defaulting to the current business day in the tz location. It writes a 400 and returns false
+// if any of them is malformed.
+func parseDateRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	loc, ok := parseLocation(w, r)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	today := service.BusinessDate(time.Now().In(loc)).Format("2006-01-02")
+	query := r.URL.Query()
+	startStr := query.Get("start_date")
+	if startStr == "" {
+		startStr = today
+	}
+	endStr := query.Get("end_date")
+	if endStr == "" {
+		endStr = today
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", startStr, loc)
+	if err != nil {
+		api.BadRequest(w, "Invalid start_date, expected YYYY-MM-DD")
+		return time.Time{}, time.Time{}, false
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", endStr, loc)
+	if err != nil {
+		api.BadRequest(w, "Invalid end_date, expected YYYY-MM-DD")
+		return time.Time{}, time.Time{}, false
+	}
+
+	return startDate, endDate, true
+}
+
+// parseTimeRange reads start_date and end_date as either RFC3339 datetimes or
+// YYYY-MM-DD business dates in the tz location, returning the half-open range [start, end).
+// A date-only end covers that whole business day and both default to the current one. It writes a
+// 400 and returns false if any parameter is malformed or the range is reversed.
+func parseTimeRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	loc, ok := parseLocation(w, r)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	query := r.URL.Query()
+	today := service.BusinessDate(time.Now().In(loc))
+
+	start := service.BusinessDayStart(today)
+	if startStr := query.Get("start_date"); startStr != "" {
+		var err error
+		if start, err = parseDateOrDateTime(startStr, loc, false); err != nil {
+			api.BadRequest(w, "Invalid start_date, expected YYYY-MM-DD or RFC3339")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	end := service.BusinessDayStart(today.AddDate(0, 0, 1))
+	if endStr := query.Get("end_date"); endStr != "" {
+		var err error
+		if end, err = parseDateOrDateTime(endStr, loc, true); err != nil {
+			api.BadRequest(w, "Invalid end_date, expected YYYY-MM-DD or RFC3339")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	if end.Before(start) {
+		api.BadRequest(w, "end_date must not be before start_date")
+		return time.Time{}, time.Time{}, false
+	}
+
+	return start, end, true
+}
+
+// parseDateOrDateTime parses an RFC3339 datetime, or a date as the start of that business day in loc.
+// With endOfDay set a date is moved to the start of the following business day.
+func parseDateOrDateTime(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1)
+	}
+	return service.BusinessDayStart(date), nil
+}
+
+// parseLocation reads the tz query parameter as an IANA time zone, defaulting to
+// the server's local zone. It writes a 400 and returns false if the zone is unknown.
+func parseLocation(w http.ResponseWriter, r *http.Request) (*time.Location, bool) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return time.Local, true
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		api.BadRequest(w, "Invalid tz, expected an IANA time zone such as Pacific/Auckland")
+		return nil, false
+	}
+	return loc, true
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+func TestWriteOrdersCSV(t *testing.T) {
+	orders := []models.Order{
+		{
+			OrderNumber: "A-001",
+			OrderedAt:   time.Date(2024, 1, 31, 23, 30, 0, 0, time.UTC),
+			Source:      models.OrderSourcePOS,
+			Total:       12.5,
+			Status:      models.OrderStatusCompleted,
+		},
+		{
+			OrderNumber: "A-002",
+			OrderedAt:   time.Date(2024, 2, 1, 0, 15, 0, 0, time.UTC),
+			Source:      models.OrderSourceOnline,
+			Total:       7,
+			Status:      models.OrderStatusCancelled,
+		},
+	}
+	loc := time.FixedZone("NZDT", 13*60*60)
+
+	export := func(orders []models.Order, failAfter int) func(func(models.Order) error) error {
+		return func(fn func(models.Order) error) error {
+			for i, order := range orders {
+				if i == failAfter {
+					return errors.New("connection reset")
+				}
+				if err := fn(order); err != nil {
+					return err
+				}
+			}
+			if failAfter >= 0 {
+				return errors.New("connection reset")
+			}
+			return nil
+		}
+	}
+
+	t.Run("header and rows", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		writeOrdersCSV(rec, "orders-2024-02-01.csv", loc, export(orders, -1))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="orders-2024-02-01.csv"` {
+			t.Errorf("Content-Disposition = %q", got)
+		}
+		want := "order_number,ordered_at,type,total,status\n" +
+			"A-001,2024-02-01T12:30:00+13:00,pos,12.50,completed\n" +
+			"A-002,2024-02-01T13:15:00+13:00,online,7.00,cancelled\n"
+		if got := rec.Body.String(); got != want {
+			t.Errorf("body = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("empty day", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		writeOrdersCSV(rec, "orders.csv", loc, export(nil, -1))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if got, want := rec.Body.String(), "order_number,ordered_at,type,total,status\n"; got != want {
+			t.Errorf("body = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("query fails", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		writeOrdersCSV(rec, "orders.csv", loc, export(orders, 0))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+		}
+		if got := rec.Header().Get("Content-Disposition"); got != "" {
+			t.Errorf("Content-Disposition = %q, want none", got)
+		}
+	})
+
+	t.Run("fails mid-stream", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != http.ErrAbortHandler {
+				t.Errorf("recovered %v, want http.ErrAbortHandler", r)
+			}
+		}()
+		writeOrdersCSV(httptest.NewRecorder(), "orders.csv", loc, export(orders, 1))
+	})
+}
@@ -0,0 +1,370 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+// StationHandler handles station HTTP requests
+type StationHandler struct {
+	stationService *service.StationService
+	hub            websockets.Broadcaster
+}
+
+// NewStationHandler creates a new station handler
+func NewStationHandler(stationService *service.StationService, hub websockets.Broadcaster) *StationHandler {
+	if hub == nil {
+		hub = websockets.Discard
+	}
+
+	return &StationHandler{
+		stationService: stationService,
+		hub:            hub,
+	}
+}
+
+// HandleStationItems handles GET /stations/{id}/items
+func (h *StationHandler) HandleStationItems(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	stationID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid station ID")
+		return
+	}
+
+	groupBy := models.StationItemsGroupBy(r.URL.Query().Get("group_by"))
+	switch groupBy {
+	case "", models.StationItemsGroupByTime, models.StationItemsGroupByOrder:
+	default:
+		api.BadRequest(w, "group_by must be one of: order, time")
+		return
+	}
+
+	items, err := h.stationService.GetStationItems(r.Context(), stationID, groupBy)
+	if err != nil {
+		log.Printf("Failed to get station items: %v", err)
+		api.InternalServerError(w, "Failed to get station items")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, items)
+}
+
+// HandleStationsItems handles GET /stations/items?station_ids=a,b,c, one combined feed for several
+// stations. Only kitchen staff, managers and admins may read it.
+func (h *StationHandler) HandleStationsItems(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	param := r.URL.Query().Get("station_ids")
+	if param == "" {
+		api.BadRequest(w, "station_ids is required")
+		return
+	}
+
+	var stationIDs []uuid.UUID
+	for _, s := range strings.Split(param, ",") {
+		id, err := uuid.Parse(strings.TrimSpace(s))
+		if err != nil {
+			api.BadRequest(w, "Invalid station ID: "+s)
+			return
+		}
+		stationIDs = append(stationIDs, id)
+	}
+
+	groupBy := models.StationItemsGroupBy(r.URL.Query().Get("group_by"))
+	switch groupBy {
+	case "", models.StationItemsGroupByTime, models.StationItemsGroupByOrder:
+	default:
+		api.BadRequest(w, "group_by must be one of: order, time")
+		return
+	}
+
+	items, err := h.stationService.GetItemsForStations(r.Context(), stationIDs, groupBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTooManyStations):
+			api.BadRequest(w, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, err.Error())
+		default:
+			log.Printf("Failed to get station items: %v", err)
+			api.InternalServerError(w, "Failed to get station items")
+		}
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, items)
+}
+
+// HandleStationAction handles POST /stations/{id}/actions
+func (h *StationHandler) HandleStationAction(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	stationID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid station ID")
+		return
+	}
+
+	var req models.StationActionRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	item, err := h.stationService.PerformAction(r.Context(), stationID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidStationAction):
+			api.BadRequest(w, "action must be one of: bump, recall, next")
+		case errors.Is(err, service.ErrItemNotAtStation), errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Item not found at this station")
+		case errors.Is(err, service.ErrNoStationItems), errors.Is(err, service.ErrInvalidItemTransition):
+			api.Conflict(w, err.Error())
+		default:
+			log.Printf("Failed to perform station action: %v", err)
+			api.InternalServerError(w, "Failed to perform station action")
+		}
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// HandleStationReorder handles POST /stations/{id}/reorder, a cook's manual order for the feed
+func (h *StationHandler) HandleStationReorder(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	stationID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid station ID")
+		return
+	}
+
+	var req models.StationReorderRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.ItemIDs) == 0 {
+		api.BadRequest(w, "item_ids is required")
+		return
+	}
+
+	items, err := h.stationService.ReorderItems(r.Context(), stationID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Station not found")
+		case errors.Is(err, service.ErrItemNotAtStation):
+			api.Unprocessable(w, "Every item must be in this station's feed")
+		case errors.Is(err, service.ErrInvalidStationAction):
+			api.BadRequest(w, err.Error())
+		default:
+			log.Printf("Failed to reorder station items: %v", err)
+			api.InternalServerError(w, "Failed to reorder station items")
+		}
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, items)
+}
+
+// HandleRoutingBulk handles POST /routing/bulk
+func (h *StationHandler) HandleRoutingBulk(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req models.RoutingBulkRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.CategoryID == uuid.Nil || req.StationID == uuid.Nil {
+		api.BadRequest(w, "category_id and station_id are required")
+		return
+	}
+
+	result, err := h.stationService.AssignCategoryRouting(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRoutingRule):
+			api.BadRequest(w, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Category or station not found")
+		case errors.Is(err, service.ErrStationInactive):
+			api.Conflict(w, "Station is inactive")
+		default:
+			log.Printf("Failed to assign routing rules: %v", err)
+			api.InternalServerError(w, "Failed to assign routing rules")
+		}
+		return
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypeRoutingUpdate, result); err != nil {
+		log.Printf("Failed to broadcast routing update: %v", err)
+	}
+
+	writeJSON(w, r, http.StatusOK, result)
+}
+
+// HandleReassignRouting handles POST /stations/{id}/reassign-routing
+func (h *StationHandler) HandleReassignRouting(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	stationID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid station ID")
+		return
+	}
+
+	var req models.RoutingReassignRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.TargetStationID == uuid.Nil {
+		api.BadRequest(w, "target_station_id is required")
+		return
+	}
+
+	result, err := h.stationService.ReassignRouting(r.Context(), stationID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSameStation):
+			api.BadRequest(w, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Station or target station not found")
+		case errors.Is(err, service.ErrStationInactive):
+			api.Conflict(w, "Target station is inactive")
+		default:
+			log.Printf("Failed to reassign routing: %v", err)
+			api.InternalServerError(w, "Failed to reassign routing")
+		}
+		return
+	}
+
+	if err := h.hub.BroadcastMessage(websockets.TypeRoutingUpdate, result); err != nil {
+		log.Printf("Failed to broadcast routing update: %v", err)
+	}
+
+	writeJSON(w, r, http.StatusOK, result)
+}
+
+// HandleStationMenuItems handles GET /stations/{id}/menu-items, the menu items routed to a station
+func (h *StationHandler) HandleStationMenuItems(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	stationID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid station ID")
+		return
+	}
+
+	items, err := h.stationService.GetRoutedMenuItems(r.Context(), stationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			api.NotFound(w, "Station not found")
+			return
+		}
+		log.Printf("Failed to get station menu items: %v", err)
+		api.InternalServerError(w, "Failed to get station menu items")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, items)
+}
+
+// HandleStationPrinters handles GET and PUT /stations/{id}/printers
+func (h *StationHandler) HandleStationPrinters(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.getStationPrinters(w, r)
+	case http.MethodPut:
+		requireManager(http.HandlerFunc(h.setStationPrinters)).ServeHTTP(w, r)
+	}
+}
+
+// getStationPrinters lists a station's printers by role
+func (h *StationHandler) getStationPrinters(w http.ResponseWriter, r *http.Request) {
+	stationID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid station ID")
+		return
+	}
+
+	printers, err := h.stationService.GetStationPrinters(r.Context(), stationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		api.NotFound(w, "Station not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get station printers: %v", err)
+		api.InternalServerError(w, "Failed to get station printers")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, printers)
+}
+
+// setStationPrinters replaces a station's printers, one per role
+func (h *StationHandler) setStationPrinters(w http.ResponseWriter, r *http.Request) {
+	stationID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid station ID")
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		api.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	var req models.StationPrintersRequest
+	if !api.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	printers, err := h.stationService.SetStationPrinters(r.Context(), stationID, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPrinterAssignment):
+			api.Unprocessable(w, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "Station not found")
+		default:
+			log.Printf("Failed to set station printers: %v", err)
+			api.InternalServerError(w, "Failed to set station printers")
+		}
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, printers)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/middleware"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+)
+
+// UserHandler handles user HTTP requests
+type UserHandler struct {
+	userService *service.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService *service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// maxUserImportSize caps the size of an uploaded roster
+const maxUserImportSize = 1 << 20 // 1MB
+
+// HandleImportUsers handles POST /users/import. The roster is either the raw
+// request body (text/csv) or the "file" field of a multipart form.
+func (h *UserHandler) HandleImportUsers(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUserImportSize)
+
+	var roster io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			api.BadRequest(w, "A CSV file is required in the file field")
+			return
+		}
+		defer file.Close()
+		roster = file
+	}
+
+	result, err := h.userService.ImportUsers(r.Context(), roster)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			api.BadRequest(w, "Import file is too large")
+		case errors.Is(err, service.ErrInvalidUserImport):
+			api.BadRequest(w, err.Error())
+		default:
+			log.Printf("Failed to import users: %v", err)
+			api.InternalServerError(w, "Failed to import users")
+		}
+		return
+	}
+
+	api.JSON(w, http.StatusOK, result)
+}
+
+// HandleUserStats handles GET /users/{id}/stats
+func (h *UserHandler) HandleUserStats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		api.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	// Managers can review anyone; everyone else only sees their own numbers
+	role, _ := middleware.GetUserRole(r.Context())
+	if role != models.RoleAdmin && role != models.RoleManager {
+		callerID, _ := middleware.GetUserID(r.Context())
+		if callerID != id.String() {
+			api.Forbidden(w, "Forbidden")
+			return
+		}
+	}
+
+	// Both dates default to today
+	startDate, endDate, ok := parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.userService.GetUserStats(r.Context(), id, startDate, endDate)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidDateRange):
+			api.BadRequest(w, "end_date must not be before start_date")
+		case errors.Is(err, repository.ErrNotFound):
+			api.NotFound(w, "User not found")
+		default:
+			log.Printf("Failed to get user stats: %v", err)
+			api.InternalServerError(w, "Failed to get user stats")
+		}
+		return
+	}
+
+	api.JSON(w, http.StatusOK, stats)
+}
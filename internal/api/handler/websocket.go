@@ -3,8 +3,6 @@ package handler
 import (
 	"net/http"
 
-	"github.com/gorilla/websocket"
-
 	"github.com/pizza-nz/restaurant-service/internal/api"
 	"github.com/pizza-nz/restaurant-service/internal/websockets"
 )
@@ -19,17 +17,12 @@ func NewWebSocketHandler(hub *websockets.Hub) *WebSocketHandler {
 	}
 }
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-
-	// TODO: Change for Prod
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
-
 func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.hub.Ready() {
+		api.ServiceUnavailable(w, "WebSocket hub is not running")
+		return
+	}
+
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
 		api.BadRequest(w, "user_id is required")
@@ -54,7 +47,7 @@ func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Upgrade the HTTP connection to a WebSocket connection
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := websockets.Upgrade(w, r, nil)
 	if err != nil {
 		// If upgrading fails, the upgrader has already written the error to the response
 		return
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the request body limit used until SetMaxBodyBytes is called
+const DefaultMaxBodyBytes = 1 << 20 // 1MB
+
+// maxBodyBytes caps the JSON request bodies DecodeJSON will read
+var maxBodyBytes int64 = DefaultMaxBodyBytes
+
+// SetMaxBodyBytes sets the largest JSON request body DecodeJSON accepts, call it before serving requests
+func SetMaxBodyBytes(n int64) {
+	if n <= 0 {
+		n = DefaultMaxBodyBytes
+	}
+	maxBodyBytes = n
+}
+
+// ReadBody reads the whole request body up to the size limit, for handlers that need the raw
+// bytes. On failure it writes a 400, or a 413 for an oversized body, and returns false.
+func ReadBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err == nil {
+		return body, true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		RequestTooLarge(w, "Request body is too large")
+	} else {
+		BadRequest(w, "Invalid request body")
+	}
+	return nil, false
+}
+
+// DecodeJSON decodes the request body into v, rejecting unknown fields and bodies over the
+// size limit. On failure it writes a 400, or a 413 for an oversized body, and returns false.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == nil {
+		return true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		RequestTooLarge(w, "Request body is too large")
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		BadRequest(w, "Unknown field "+strings.TrimPrefix(err.Error(), "json: unknown field "))
+	default:
+		BadRequest(w, "Invalid request body")
+	}
+	return false
+}
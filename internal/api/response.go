@@ -0,0 +1,13 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// JSON writes v as a JSON response with the given status code
+func JSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -12,16 +12,68 @@ type Config struct {
 	Database Database `yaml:"database"`
 
 	JWT JWT `yaml:"jwt"`
+
+	Orders Orders `yaml:"orders"`
+
+	Menu Menu `yaml:"menu"`
+
+	WebSocket WebSocket `yaml:"websocket"`
+
+	Integrations Integrations `yaml:"integrations"`
 }
 
 type Server struct {
 	Address string `yaml:"address"`
-	Mode    string `yaml:"address"`
+	Mode    string `yaml:"mode"`
+
+	ReadHeaderTimeout int `yaml:"read_header_timeout"` // In Seconds
+	ReadTimeout       int `yaml:"read_timeout"`        // In Seconds
+	WriteTimeout      int `yaml:"write_timeout"`       // In Seconds, WebSocket connections are exempt
+	IdleTimeout       int `yaml:"idle_timeout"`        // In Seconds
+
+	MaxBodyBytes int64 `yaml:"max_body_bytes"` // Largest JSON request body accepted, defaults to 1MB
+
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins"` // Browser origins that may call the API, "*" for any, empty for none
 }
 
 type JWT struct {
-	Secret    string `yaml:"secret"`
-	ExpiresIn int    `yaml:"expires_in"` // In Hours
+	Secret          string   `yaml:"secret"`
+	ExpiresIn       int      `yaml:"expires_in"`       // In Hours
+	Algorithm       string   `yaml:"algorithm"`        // HS256, HS384 or HS512
+	PreviousSecrets []string `yaml:"previous_secrets"` // Still accepted for validation during key rotation
+}
+
+type Orders struct {
+	AutoCancelEnabled  bool   `yaml:"auto_cancel_enabled"`
+	AutoCancelAfter    int    `yaml:"auto_cancel_after"`    // In Minutes
+	AutoCancelInterval int    `yaml:"auto_cancel_interval"` // In Seconds
+	AutoComplete       *bool  `yaml:"auto_complete_orders"` // Defaults to true, false leaves finished orders ready
+	NumberFormat       string `yaml:"number_format"`        // {seq} and optionally {date}, defaults to {date}-{seq}
+	BusinessDayCutoff  string `yaml:"business_day_cutoff"`  // HH:MM, orders before it count toward the previous day
+	CurrencyMinorUnits *int   `yaml:"currency_minor_units"` // Decimal places prices round to, 0 to 2, defaults to 2
+}
+
+type Menu struct {
+	MaxModifierOptions int `yaml:"max_modifier_options"` // Options allowed per modifier, defaults to 50
+}
+
+type WebSocket struct {
+	AllowedOrigins    []string `yaml:"allowed_origins"`   // Empty allows every origin
+	ReadBufferSize    int      `yaml:"read_buffer_size"`  // In Bytes
+	WriteBufferSize   int      `yaml:"write_buffer_size"` // In Bytes
+	EnableCompression bool     `yaml:"enable_compression"`
+
+	DisplayCheckInterval int `yaml:"display_check_interval"` // In Seconds, how often display heartbeats are checked
+	DisplayOfflineAfter  int `yaml:"display_offline_after"`  // In Seconds without a heartbeat before a display is offline
+}
+
+type Integrations struct {
+	Providers map[string]IntegrationProvider `yaml:"providers"` // By provider name, e.g. "generic"
+}
+
+type IntegrationProvider struct {
+	Secret   string `yaml:"secret"`   // Shared key the platform signs webhooks with, HMAC-SHA256
+	Username string `yaml:"username"` // User the platform's orders are recorded against
 }
 
 type Database struct {
@@ -31,6 +83,15 @@ type Database struct {
 	Password string `yaml:"password"`
 	DBName   string `yaml:"dbname"`
 	SSLMode  string `yaml:"sslmode"`
+
+	MaxRetries    int `yaml:"max_retries"`    // Connection attempts at startup
+	RetryBackoff  int `yaml:"retry_backoff"`  // In Seconds, multiplied by the attempt number
+	PingTimeout   int `yaml:"ping_timeout"`   // In Seconds
+	QueryTimeout  int `yaml:"query_timeout"`  // In Seconds, the most one API request may spend on the database
+	ExportTimeout int `yaml:"export_timeout"` // In Seconds, replaces query_timeout for report exports
+
+	MigrationsPath     string `yaml:"migrations_path"`
+	MigrationsEmbedded bool   `yaml:"migrations_embedded"` // Use the migrations compiled into the binary
 }
 
 func Load() (*Config, error) {
@@ -51,5 +112,81 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.Server.ReadHeaderTimeout <= 0 {
+		cfg.Server.ReadHeaderTimeout = 5
+	}
+	if cfg.Server.ReadTimeout <= 0 {
+		cfg.Server.ReadTimeout = 15
+	}
+	if cfg.Server.WriteTimeout <= 0 {
+		cfg.Server.WriteTimeout = 30
+	}
+	if cfg.Server.IdleTimeout <= 0 {
+		cfg.Server.IdleTimeout = 120
+	}
+	if cfg.Server.MaxBodyBytes <= 0 {
+		cfg.Server.MaxBodyBytes = 1 << 20
+	}
+
+	if cfg.Database.QueryTimeout <= 0 {
+		cfg.Database.QueryTimeout = 10
+	}
+	if cfg.Database.ExportTimeout <= 0 {
+		cfg.Database.ExportTimeout = 120
+	}
+	if cfg.Database.MaxRetries <= 0 {
+		cfg.Database.MaxRetries = 5
+	}
+	if cfg.Database.RetryBackoff <= 0 {
+		cfg.Database.RetryBackoff = 2
+	}
+	if cfg.Database.PingTimeout <= 0 {
+		cfg.Database.PingTimeout = 5
+	}
+
+	if cfg.Database.MigrationsPath == "" {
+		cfg.Database.MigrationsPath = "migrations"
+	}
+	if envPath := os.Getenv("MIGRATIONS_PATH"); envPath != "" {
+		cfg.Database.MigrationsPath = envPath
+	}
+
+	if cfg.Orders.AutoCancelAfter <= 0 {
+		cfg.Orders.AutoCancelAfter = 240
+	}
+	if cfg.Orders.AutoCancelInterval <= 0 {
+		cfg.Orders.AutoCancelInterval = 300
+	}
+	if cfg.Orders.BusinessDayCutoff == "" {
+		cfg.Orders.BusinessDayCutoff = "00:00"
+	}
+	if cfg.Orders.NumberFormat == "" {
+		cfg.Orders.NumberFormat = "{date}-{seq}"
+	}
+	if cfg.Orders.CurrencyMinorUnits == nil {
+		minorUnits := 2
+		cfg.Orders.CurrencyMinorUnits = &minorUnits
+	}
+	if cfg.Menu.MaxModifierOptions <= 0 {
+		cfg.Menu.MaxModifierOptions = 50
+	}
+	if cfg.Orders.AutoComplete == nil {
+		autoComplete := true
+		cfg.Orders.AutoComplete = &autoComplete
+	}
+
+	if cfg.WebSocket.ReadBufferSize <= 0 {
+		cfg.WebSocket.ReadBufferSize = 1024
+	}
+	if cfg.WebSocket.WriteBufferSize <= 0 {
+		cfg.WebSocket.WriteBufferSize = 1024
+	}
+	if cfg.WebSocket.DisplayCheckInterval <= 0 {
+		cfg.WebSocket.DisplayCheckInterval = 30
+	}
+	if cfg.WebSocket.DisplayOfflineAfter <= 0 {
+		cfg.WebSocket.DisplayOfflineAfter = 90
+	}
+
 	return &cfg, nil
 }
@@ -4,14 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/pizza-nz/restaurant-service/internal/config"
+	"github.com/pizza-nz/restaurant-service/migrations"
 )
 
 type Postgres struct {
@@ -27,18 +31,23 @@ func NewPostgres(cfg config.Database) (*Postgres, error) {
 	var db *sqlx.DB
 	var err error
 
-	maxRetries := 5
+	maxRetries := max(cfg.MaxRetries, 1)
+	backoff := time.Duration(cfg.RetryBackoff) * time.Second
+	started := time.Now()
 	for i := 0; i < maxRetries; i++ {
 		db, err = sqlx.Connect("postgres", connStr)
 		if err == nil {
 			break
 		}
 		log.Printf("Failed to connect to database (attempt %d/%d): %v", i+1, maxRetries, err)
-		time.Sleep(time.Duration(i+1) * 2 * time.Second) // Exponential backoff
+		if i < maxRetries-1 {
+			time.Sleep(time.Duration(i+1) * backoff) // Linear backoff
+		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("could not connect to database after %d attempts: %w", maxRetries, err)
+		return nil, fmt.Errorf("could not connect to database after %d attempts over %s: %w",
+			maxRetries, time.Since(started).Round(time.Second), err)
 	}
 
 	// Configure connection pool for low-resource environment
@@ -49,11 +58,15 @@ func NewPostgres(cfg config.Database) (*Postgres, error) {
 	db.SetConnMaxIdleTime(30 * time.Minute) // Close idle connections
 
 	// Verify connection is working
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	pingTimeout := time.Duration(cfg.PingTimeout) * time.Second
+	if pingTimeout <= 0 {
+		pingTimeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("could not ping database: %w", err)
+		return nil, fmt.Errorf("could not ping database within %s: %w", pingTimeout, err)
 	}
 
 	return &Postgres{DB: db}, nil
@@ -85,15 +98,44 @@ func (p *Postgres) Close() error {
 	return p.DB.Close()
 }
 
+// VerifyMigrations checks that the configured migrations source is usable
+func VerifyMigrations(cfg config.Database) error {
+	if cfg.MigrationsEmbedded {
+		return nil
+	}
+
+	info, err := os.Stat(cfg.MigrationsPath)
+	if err != nil {
+		return fmt.Errorf("migrations directory %q not found: %w", cfg.MigrationsPath, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("migrations path %q is not a directory", cfg.MigrationsPath)
+	}
+
+	return nil
+}
+
 // Migrate runs database migrations
 func (p *Postgres) Migrate(cfg config.Database) error {
-	// Set up migration source and target
-	migrationsPath := "file://migrations"
+	if err := VerifyMigrations(cfg); err != nil {
+		return err
+	}
+
 	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode)
 
-	// Initialize migrate instance
-	m, err := migrate.New(migrationsPath, dbURL)
+	// Initialize migrate instance from the embedded or on-disk source
+	var m *migrate.Migrate
+	var err error
+	if cfg.MigrationsEmbedded {
+		source, srcErr := iofs.New(migrations.FS, ".")
+		if srcErr != nil {
+			return fmt.Errorf("failed to load embedded migrations: %w", srcErr)
+		}
+		m, err = migrate.NewWithSourceInstance("iofs", source, dbURL)
+	} else {
+		m, err = migrate.New("file://"+filepath.ToSlash(cfg.MigrationsPath), dbURL)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to initialize migrate: %w", err)
 	}
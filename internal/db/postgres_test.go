@@ -0,0 +1,76 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"github.com/pizza-nz/restaurant-service/internal/config"
+	"github.com/pizza-nz/restaurant-service/migrations"
+)
+
+func TestVerifyMigrations(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "000001_init.up.sql")
+	if err := os.WriteFile(file, []byte("SELECT 1;"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     config.Database
+		wantErr bool
+	}{
+		{"directory", config.Database{MigrationsPath: dir}, false},
+		{"missing path", config.Database{MigrationsPath: filepath.Join(dir, "missing")}, true},
+		{"file instead of directory", config.Database{MigrationsPath: file}, true},
+		{"embedded ignores the path", config.Database{MigrationsPath: filepath.Join(dir, "missing"), MigrationsEmbedded: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyMigrations(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyMigrations() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		t.Fatalf("no embedded migrations: %v", err)
+	}
+	if version != 1 {
+		t.Errorf("first migration = %d, want 1", version)
+	}
+
+	// Every migration can be read both ways
+	for {
+		up, _, err := source.ReadUp(version)
+		if err != nil {
+			t.Errorf("migration %d up: %v", version, err)
+		} else {
+			up.Close()
+		}
+		down, _, err := source.ReadDown(version)
+		if err != nil {
+			t.Errorf("migration %d down: %v", version, err)
+		} else {
+			down.Close()
+		}
+
+		next, err := source.Next(version)
+		if err != nil {
+			break
+		}
+		version = next
+	}
+}
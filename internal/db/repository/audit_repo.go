@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+// AuditRepository handles audit log data access
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create records an audit log entry
+func (r *AuditRepository) Create(ctx context.Context, entry models.AuditLog) error {
+	return insertAuditLog(ctx, r.db, entry)
+}
+
+// List returns one page of entries matching filter, newest first, along with the total match count
+func (r *AuditRepository) List(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLog, int, error) {
+	where := " WHERE 1 = 1"
+	var args []interface{}
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		where += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		where += fmt.Sprintf(" AND action = $%d", len(args))
+	}
+	if filter.TableName != "" {
+		args = append(args, filter.TableName)
+		where += fmt.Sprintf(" AND table_name = $%d", len(args))
+	}
+	if filter.Start != nil {
+		args = append(args, *filter.Start)
+		where += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if filter.End != nil {
+		args = append(args, *filter.End)
+		where += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	var total int
+	err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM audit_logs"+where, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	query := `
+		SELECT id, user_id, action, table_name, record_id, old_values, new_values, created_at
+		FROM audit_logs` + where + fmt.Sprintf(`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	entries := []models.AuditLog{}
+	err = r.db.SelectContext(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// insertAuditLog writes an audit entry, allowing callers to include it in their own transaction
+func insertAuditLog(ctx context.Context, exec sqlx.ExecerContext, entry models.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (user_id, action, table_name, record_id, old_values, new_values)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	var oldValues, newValues interface{}
+	if len(entry.OldValues) > 0 {
+		oldValues = []byte(entry.OldValues)
+	}
+	if len(entry.NewValues) > 0 {
+		newValues = []byte(entry.NewValues)
+	}
+
+	_, err := exec.ExecContext(ctx, query, entry.UserID, entry.Action, entry.TableName, entry.RecordID, oldValues, newValues)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
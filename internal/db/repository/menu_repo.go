@@ -2,15 +2,22 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pizza-nz/restaurant-service/internal/models"
 )
 
+// ErrDuplicatePLU is returned when a live menu item already uses the PLU
+var ErrDuplicatePLU = errors.New("PLU already in use")
+
 // MenuRepository handles menu data access
 type MenuRepository struct {
 	db *sqlx.DB
@@ -21,17 +28,13 @@ func NewMenuRepository(db *sqlx.DB) *MenuRepository {
 	return &MenuRepository{db: db}
 }
 
-// BeginTransaction begins a new transaction
+// beginTransaction begins a new transaction
+// Callers are responsible for rolling back on error
 func (r *MenuRepository) beginTransaction(ctx context.Context) (*sqlx.Tx, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
 
 	return tx, nil
 }
@@ -39,7 +42,7 @@ func (r *MenuRepository) beginTransaction(ctx context.Context) (*sqlx.Tx, error)
 // GetCategoryByID retrieves a menu category by ID
 func (r *MenuRepository) GetCategoryByID(ctx context.Context, id uuid.UUID) (*models.MenuCategory, error) {
 	query := `
-		SELECT id, name, display_order, color_code, created_at, updated_at
+		SELECT id, name, display_order, color_code, default_station_id, created_at, updated_at
 		FROM menu_categories
 		WHERE id = $1
 	`
@@ -47,7 +50,7 @@ func (r *MenuRepository) GetCategoryByID(ctx context.Context, id uuid.UUID) (*mo
 	var category models.MenuCategory
 	err := r.db.GetContext(ctx, &category, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get menu category: %w", err)
+		return nil, fmt.Errorf("failed to get menu category: %w", notFound(err))
 	}
 
 	return &category, nil
@@ -56,12 +59,12 @@ func (r *MenuRepository) GetCategoryByID(ctx context.Context, id uuid.UUID) (*mo
 // ListCategories retrieves all menu categories
 func (r *MenuRepository) ListCategories(ctx context.Context) ([]models.MenuCategory, error) {
 	query := `
-		SELECT id, name, display_order, color_code, created_at, updated_at
+		SELECT id, name, display_order, color_code, default_station_id, created_at, updated_at
 		FROM menu_categories
 		ORDER BY display_order ASC, name ASC
 	`
 
-	var categories []models.MenuCategory
+	categories := []models.MenuCategory{}
 	err := r.db.SelectContext(ctx, &categories, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list menu categories: %w", err)
@@ -73,9 +76,9 @@ func (r *MenuRepository) ListCategories(ctx context.Context) ([]models.MenuCateg
 // CreateCategory creates a new menu category
 func (r *MenuRepository) CreateCategory(ctx context.Context, category models.MenuCategory) (*models.MenuCategory, error) {
 	query := `
-		INSERT INTO menu_categories (name, display_order, color_code)
-		VALUES ($1, $2, $3)
-		RETURNING id, name, display_order, color_code, created_at, updated_at
+		INSERT INTO menu_categories (name, display_order, color_code, default_station_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, name, display_order, color_code, default_station_id, created_at, updated_at
 	`
 
 	var createdCategory models.MenuCategory
@@ -86,6 +89,7 @@ func (r *MenuRepository) CreateCategory(ctx context.Context, category models.Men
 		category.Name,
 		category.DisplayOrder,
 		category.ColorCode,
+		category.DefaultStationID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create menu category: %w", err)
@@ -98,9 +102,9 @@ func (r *MenuRepository) CreateCategory(ctx context.Context, category models.Men
 func (r *MenuRepository) UpdateCategory(ctx context.Context, category models.MenuCategory) (*models.MenuCategory, error) {
 	query := `
 		UPDATE menu_categories
-		SET name = $1, display_order = $2, color_code = $3, updated_at = $4
-		WHERE id = $5
-		RETURNING id, name, display_order, color_code, created_at, updated_at
+		SET name = $1, display_order = $2, color_code = $3, default_station_id = $4, updated_at = $5
+		WHERE id = $6
+		RETURNING id, name, display_order, color_code, default_station_id, created_at, updated_at
 	`
 
 	var updatedCategory models.MenuCategory
@@ -111,6 +115,7 @@ func (r *MenuRepository) UpdateCategory(ctx context.Context, category models.Men
 		category.Name,
 		category.DisplayOrder,
 		category.ColorCode,
+		category.DefaultStationID,
 		time.Now(),
 		category.ID,
 	)
@@ -139,16 +144,69 @@ func (r *MenuRepository) DeleteCategory(ctx context.Context, id uuid.UUID) error
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("menu category not found")
+		return fmt.Errorf("menu category %w", ErrNotFound)
 	}
 
 	return nil
 }
 
+// MergeCategories moves all items from the source category into the target
+// and deletes the source, returning the number of items moved
+func (r *MenuRepository) MergeCategories(ctx context.Context, sourceID, targetID uuid.UUID) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Reassign items, including soft-deleted ones so the cascade doesn't remove them
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE menu_items SET category_id = $1, updated_at = $2 WHERE category_id = $3",
+		targetID, time.Now(), sourceID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign menu items: %w", err)
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// Delete the source category
+	result, err = tx.ExecContext(ctx, "DELETE FROM menu_categories WHERE id = $1", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete source category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		err = fmt.Errorf("menu category %w", ErrNotFound)
+		return 0, err
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return moved, nil
+}
+
 // GetItemByID retrieves a menu item by ID
 func (r *MenuRepository) GetItemByID(ctx context.Context, id uuid.UUID) (*models.MenuItem, error) {
 	query := `
-		SELECT id, category_id, name, price, available, description, image_path, created_at, updated_at
+		SELECT id, category_id, name, price, unit, available, is_featured, ordering_weight, description, image_path, kitchen_notes, plu, deleted_at, created_by, updated_by, created_at, updated_at
 		FROM menu_items
 		WHERE id = $1
 	`
@@ -156,7 +214,7 @@ func (r *MenuRepository) GetItemByID(ctx context.Context, id uuid.UUID) (*models
 	var item models.MenuItem
 	err := r.db.GetContext(ctx, &item, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get menu item: %w", err)
+		return nil, fmt.Errorf("failed to get menu item: %w", notFound(err))
 	}
 
 	// Get associated category
@@ -173,6 +231,13 @@ func (r *MenuRepository) GetItemByID(ctx context.Context, id uuid.UUID) (*models
 	}
 	item.Modifiers = modifiers
 
+	// Get tags
+	tags, err := r.getTagsForItems(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, err
+	}
+	item.Tags = tags[id]
+
 	return &item, nil
 }
 
@@ -180,38 +245,35 @@ func (r *MenuRepository) GetItemByID(ctx context.Context, id uuid.UUID) (*models
 func (r *MenuRepository) GetItemModifiers(ctx context.Context, itemID uuid.UUID) ([]models.MenuItemModifier, error) {
 	query := `
 		SELECT mim.id, mim.menu_item_id, mim.modifier_id, mim.required, mim.created_at,
-		       m.id as "modifier.id", m.name as "modifier.name", m.is_multiple as "modifier.is_multiple"
+		       m.name as modifier_name, m.is_multiple as modifier_is_multiple,
+		       m.min_selections as modifier_min_selections, m.max_selections as modifier_max_selections
 		FROM menu_item_modifiers mim
 		JOIN modifiers m ON mim.modifier_id = m.id
 		WHERE mim.menu_item_id = $1
+		ORDER BY m.name ASC
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, itemID)
+	var rows []struct {
+		models.MenuItemModifier
+		ModifierName          string `db:"modifier_name"`
+		ModifierIsMultiple    bool   `db:"modifier_is_multiple"`
+		ModifierMinSelections int    `db:"modifier_min_selections"`
+		ModifierMaxSelections *int   `db:"modifier_max_selections"`
+	}
+	err := r.db.SelectContext(ctx, &rows, query, itemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query item modifiers: %w", err)
 	}
-	defer rows.Close()
-
-	var modifiers []models.MenuItemModifier
-	for rows.Next() {
-		var mim models.MenuItemModifier
-		var modifier models.Modifier
-
-		// Use a map to handle the nested structure
-		dest := map[string]interface{}{
-			"id":                   &mim.ID,
-			"menu_item_id":         &mim.MenuItemID,
-			"modifier_id":          &mim.ModifierID,
-			"required":             &mim.Required,
-			"created_at":           &mim.CreatedAt,
-			"modifier.id":          &modifier.ID,
-			"modifier.name":        &modifier.Name,
-			"modifier.is_multiple": &modifier.IsMultiple,
-		}
 
-		err := rows.MapScan(dest)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan modifier row: %w", err)
+	modifiers := make([]models.MenuItemModifier, 0, len(rows))
+	for _, row := range rows {
+		mim := row.MenuItemModifier
+		modifier := models.Modifier{
+			ID:            mim.ModifierID,
+			Name:          row.ModifierName,
+			IsMultiple:    row.ModifierIsMultiple,
+			MinSelections: row.ModifierMinSelections,
+			MaxSelections: row.ModifierMaxSelections,
 		}
 
 		// Get options for this modifier
@@ -231,7 +293,7 @@ func (r *MenuRepository) GetItemModifiers(ctx context.Context, itemID uuid.UUID)
 // GetModifierOptions retrieves options for a modifier
 func (r *MenuRepository) GetModifierOptions(ctx context.Context, modifierID uuid.UUID) ([]models.ModifierOption, error) {
 	query := `
-		SELECT id, modifier_id, name, price_adjustment, created_at, updated_at
+		SELECT id, modifier_id, name, price_adjustment, adjustment_type, created_at, updated_at
 		FROM modifier_options
 		WHERE modifier_id = $1
 		ORDER BY name ASC
@@ -246,25 +308,42 @@ func (r *MenuRepository) GetModifierOptions(ctx context.Context, modifierID uuid
 	return options, nil
 }
 
-// ListItems retrieves all menu items, optionally filtered by category
-func (r *MenuRepository) ListItems(ctx context.Context, categoryID *uuid.UUID) ([]models.MenuItem, error) {
-	var query string
+// GetItemByPLU retrieves the live menu item with the given PLU
+func (r *MenuRepository) GetItemByPLU(ctx context.Context, plu string) (*models.MenuItem, error) {
+	var id uuid.UUID
+	err := r.db.GetContext(ctx, &id, "SELECT id FROM menu_items WHERE plu = $1 AND deleted_at IS NULL", plu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu item by PLU: %w", notFound(err))
+	}
+
+	return r.GetItemByID(ctx, id)
+}
+
+// ListItems retrieves all menu items matching filter
+func (r *MenuRepository) ListItems(ctx context.Context, filter models.MenuItemFilter) ([]models.MenuItem, error) {
+	query := `
+		SELECT id, category_id, name, price, unit, available, is_featured, ordering_weight, description, image_path, kitchen_notes, plu, deleted_at, created_by, updated_by, created_at, updated_at
+		FROM menu_items
+		WHERE deleted_at IS NULL
+	`
 	var args []interface{}
 
-	if categoryID != nil {
-		query = `
-			SELECT id, category_id, name, price, available, description, image_path, created_at, updated_at
-			FROM menu_items
-			WHERE category_id = $1
-			ORDER BY name ASC
-		`
-		args = append(args, *categoryID)
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		query += fmt.Sprintf(" AND category_id = $%d", len(args))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM menu_item_tags t WHERE t.menu_item_id = menu_items.id AND t.tag = $%d)", len(args))
+	}
+	if filter.Featured != nil {
+		args = append(args, *filter.Featured)
+		query += fmt.Sprintf(" AND is_featured = $%d", len(args))
+	}
+	if filter.FeaturedFirst {
+		query += " ORDER BY is_featured DESC, CASE WHEN is_featured THEN ordering_weight END DESC NULLS LAST, name ASC"
 	} else {
-		query = `
-			SELECT id, category_id, name, price, available, description, image_path, created_at, updated_at
-			FROM menu_items
-			ORDER BY name ASC
-		`
+		query += " ORDER BY name ASC"
 	}
 
 	var items []models.MenuItem
@@ -286,27 +365,102 @@ func (r *MenuRepository) ListItems(ctx context.Context, categoryID *uuid.UUID) (
 		items[i].Category = categories[items[i].CategoryID]
 	}
 
+	// Load every item's tags in one query
+	ids := make([]uuid.UUID, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	tags, err := r.getTagsForItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Tags = tags[items[i].ID]
+	}
+
 	return items, nil
 }
 
-// CreateItem creates a new menu item with modifiers and routing
-func (r *MenuRepository) CreateItem(ctx context.Context, tx *sqlx.Tx, item models.MenuItem, modifierIDs []uuid.UUID, stationID uuid.UUID) (*models.MenuItem, error) {
-	// Determine if we're using a provided transaction or creating our own
-	var err error
+// ListTags retrieves the managed tag list
+// An empty list means any tag is allowed
+func (r *MenuRepository) ListTags(ctx context.Context) ([]string, error) {
+	var tags []string
+	err := r.db.SelectContext(ctx, &tags, "SELECT name FROM menu_tags ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list menu tags: %w", err)
+	}
+
+	return tags, nil
+}
 
-	// Verify transaction in process
-	if tx == nil {
-		tx, err = r.beginTransaction(ctx)
+// getTagsForItems retrieves the tags for several menu items, keyed by item ID
+func (r *MenuRepository) getTagsForItems(ctx context.Context, itemIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tags := make(map[uuid.UUID][]string, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return tags, nil
+	}
+
+	ids := make([]string, len(itemIDs))
+	for i, id := range itemIDs {
+		ids[i] = id.String()
+		tags[id] = []string{}
+	}
+
+	var rows []struct {
+		MenuItemID uuid.UUID `db:"menu_item_id"`
+		Tag        string    `db:"tag"`
+	}
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		"SELECT menu_item_id, tag FROM menu_item_tags WHERE menu_item_id = ANY($1::uuid[]) ORDER BY tag ASC",
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item tags: %w", err)
+	}
+
+	for _, row := range rows {
+		tags[row.MenuItemID] = append(tags[row.MenuItemID], row.Tag)
+	}
+
+	return tags, nil
+}
+
+// setItemTags replaces a menu item's tags within tx
+func (r *MenuRepository) setItemTags(ctx context.Context, tx *sqlx.Tx, itemID uuid.UUID, tags []string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM menu_item_tags WHERE menu_item_id = $1", itemID)
+	if err != nil {
+		return fmt.Errorf("failed to remove existing tags: %w", err)
+	}
+
+	for _, tag := range tags {
+		_, err = tx.ExecContext(ctx, "INSERT INTO menu_item_tags (menu_item_id, tag) VALUES ($1, $2)", itemID, tag)
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin transaction: %w", err)
+			return fmt.Errorf("failed to add tag to item: %w", err)
 		}
 	}
 
+	return nil
+}
+
+// CreateItem creates a new menu item with modifiers and routing
+func (r *MenuRepository) CreateItem(ctx context.Context, item models.MenuItem, modifierIDs, requiredModifierIDs []uuid.UUID, stationID uuid.UUID) (*models.MenuItem, error) {
+	tx, err := r.beginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
 	// Insert the menu item
 	query := `
-		INSERT INTO menu_items (category_id, name, price, available, description, image_path)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, category_id, name, price, available, description, image_path, created_at, updated_at
+		INSERT INTO menu_items (category_id, name, price, unit, available, is_featured, ordering_weight, description, image_path, kitchen_notes, plu, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+		RETURNING id, category_id, name, price, unit, available, is_featured, ordering_weight, description, image_path, kitchen_notes, plu, deleted_at, created_by, updated_by, created_at, updated_at
 	`
 
 	var createdItem models.MenuItem
@@ -317,10 +471,19 @@ func (r *MenuRepository) CreateItem(ctx context.Context, tx *sqlx.Tx, item model
 		item.CategoryID,
 		item.Name,
 		item.Price,
+		item.Unit,
 		item.Available,
+		item.IsFeatured,
+		item.OrderingWeight,
 		item.Description,
 		item.ImagePath,
+		item.KitchenNotes,
+		item.PLU,
+		item.CreatedBy,
 	)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicatePLU, *item.PLU)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create menu item: %w", err)
 	}
@@ -330,7 +493,7 @@ func (r *MenuRepository) CreateItem(ctx context.Context, tx *sqlx.Tx, item model
 		_, err = tx.ExecContext(
 			ctx,
 			`INSERT INTO menu_item_modifiers (menu_item_id, modifier_id, required) VALUES ($1, $2, $3)`,
-			createdItem.ID, modID, false,
+			createdItem.ID, modID, slices.Contains(requiredModifierIDs, modID),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to add modifier to item: %w", err)
@@ -347,57 +510,76 @@ func (r *MenuRepository) CreateItem(ctx context.Context, tx *sqlx.Tx, item model
 		return nil, fmt.Errorf("failed to add routing rule for item: %w", err)
 	}
 
-	// If we started the transaction, we'll commit it in the defer function
+	// Add tags
+	err = r.setItemTags(ctx, tx, createdItem.ID, item.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
 	// Get the fully populated item
 	return r.GetItemByID(ctx, createdItem.ID)
 }
 
-// UpdateItem updates a menu item
-func (r *MenuRepository) UpdateItem(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, req models.MenuItemRequest) (*models.MenuItem, error) {
-	var err error
-
-	// Verify transaction in process
-	if tx == nil {
-		tx, err = r.beginTransaction(ctx)
+// UpdateItem updates a menu item along with its modifiers and routing, recording userID as the editor
+func (r *MenuRepository) UpdateItem(ctx context.Context, id, userID uuid.UUID, req models.MenuItemRequest) (*models.MenuItem, error) {
+	tx, err := r.beginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
 		if err != nil {
-			return nil, fmt.Errorf("failed to begin transaction: %w", err)
+			_ = tx.Rollback()
 		}
+	}()
+
+	oldPrice, err := lockItemPrice(ctx, tx, id)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update the menu item
+	now := time.Now()
 	_, err = tx.Exec(`
 		UPDATE menu_items
-		SET category_id = $1, name = $2, price = $3, available = $4, description = $5, image_path = $6, updated_at = $7
-		WHERE id = $8
+		SET category_id = $1, name = $2, price = $3, unit = $4, available = $5, is_featured = $6, ordering_weight = $7,
+		    description = $8, image_path = $9, kitchen_notes = $10, plu = $11, updated_at = $12, updated_by = $13
+		WHERE id = $14
 	`,
 		req.CategoryID,
 		req.Name,
 		req.Price,
+		req.Unit,
 		req.Available,
+		req.IsFeatured,
+		req.OrderingWeight,
 		req.Description,
 		req.ImagePath,
-		time.Now(),
+		req.KitchenNotes,
+		req.PLU,
+		now,
+		userID,
 		id,
 	)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicatePLU, *req.PLU)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to update menu item: %w", err)
 	}
 
-	// Update modifiers (remove existing ones and add new ones)
-	_, err = tx.Exec("DELETE FROM menu_item_modifiers WHERE menu_item_id = $1", id)
+	err = insertPriceChange(ctx, tx, id, userID, oldPrice, req.Price, now)
 	if err != nil {
-		return nil, fmt.Errorf("failed to remove existing modifiers: %w", err)
+		return nil, err
 	}
 
-	for _, modID := range req.ModifierIDs {
-		_, err = tx.Exec(
-			"INSERT INTO menu_item_modifiers (menu_item_id, modifier_id, required) VALUES ($1, $2, $3)",
-			id, modID, false,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add modifier: %w", err)
-		}
+	// Update modifiers (remove existing ones and add new ones)
+	err = r.replaceItemModifiers(ctx, tx, id, req.ModifierIDs, req.RequiredModifierIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update routing rule if station ID changed
@@ -406,36 +588,204 @@ func (r *MenuRepository) UpdateItem(ctx context.Context, tx *sqlx.Tx, id uuid.UU
 		return nil, fmt.Errorf("invalid station ID: %w", err)
 	}
 
+	err = r.setItemStation(ctx, tx, id, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace tags
+	err = r.setItemTags(ctx, tx, id, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetItemByID(ctx, id)
+}
+
+// PatchItem updates only the fields set on patch, recording userID as the editor.
+// Modifiers, routing and tags are left alone unless they are part of the patch.
+func (r *MenuRepository) PatchItem(ctx context.Context, id, userID uuid.UUID, patch models.MenuItemPatchRequest) (*models.MenuItem, error) {
+	tx, err := r.beginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var oldPrice float64
+	if patch.Price != nil {
+		oldPrice, err = lockItemPrice(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	set := "updated_at = $1, updated_by = $2"
+	args := []interface{}{now, userID}
+	column := func(name string, value interface{}) {
+		args = append(args, value)
+		set += fmt.Sprintf(", %s = $%d", name, len(args))
+	}
+
+	if patch.CategoryID != nil {
+		column("category_id", *patch.CategoryID)
+	}
+	if patch.Name != nil {
+		column("name", *patch.Name)
+	}
+	if patch.Price != nil {
+		column("price", *patch.Price)
+	}
+	if patch.Unit != nil {
+		column("unit", *patch.Unit)
+	}
+	if patch.Available != nil {
+		column("available", *patch.Available)
+	}
+	if patch.IsFeatured != nil {
+		column("is_featured", *patch.IsFeatured)
+	}
+	if patch.OrderingWeight != nil {
+		column("ordering_weight", *patch.OrderingWeight)
+	}
+	if patch.Description != nil {
+		column("description", *patch.Description)
+	}
+	if patch.ImagePath != nil {
+		column("image_path", *patch.ImagePath)
+	}
+	if patch.KitchenNotes != nil {
+		column("kitchen_notes", *patch.KitchenNotes)
+	}
+	if patch.PLU != nil {
+		var plu *string
+		if *patch.PLU != "" {
+			plu = patch.PLU
+		}
+		column("plu", plu)
+	}
+
+	args = append(args, id)
+	result, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE menu_items SET %s WHERE id = $%d AND deleted_at IS NULL", set, len(args)),
+		args...,
+	)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicatePLU, *patch.PLU)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update menu item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		err = fmt.Errorf("menu item %w", ErrNotFound)
+		return nil, err
+	}
+
+	if patch.Price != nil {
+		err = insertPriceChange(ctx, tx, id, userID, oldPrice, *patch.Price, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.ModifierIDs != nil {
+		var required []uuid.UUID
+		if patch.RequiredModifierIDs != nil {
+			required = *patch.RequiredModifierIDs
+		}
+		err = r.replaceItemModifiers(ctx, tx, id, *patch.ModifierIDs, required)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.StationID != nil {
+		err = r.setItemStation(ctx, tx, id, *patch.StationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Tags != nil {
+		err = r.setItemTags(ctx, tx, id, *patch.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetItemByID(ctx, id)
+}
+
+// replaceItemModifiers swaps an item's modifiers for modifierIDs, marking those in requiredIDs as required
+func (r *MenuRepository) replaceItemModifiers(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, modifierIDs, requiredIDs []uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM menu_item_modifiers WHERE menu_item_id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to remove existing modifiers: %w", err)
+	}
+
+	for _, modID := range modifierIDs {
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO menu_item_modifiers (menu_item_id, modifier_id, required) VALUES ($1, $2, $3)",
+			id, modID, slices.Contains(requiredIDs, modID),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add modifier: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// setItemStation points an item's primary routing rule at stationID, creating the rule if needed
+func (r *MenuRepository) setItemStation(ctx context.Context, tx *sqlx.Tx, id, stationID uuid.UUID) error {
 	// Check if there's an existing routing rule
 	var ruleID uuid.UUID
-	err = tx.Get(&ruleID, "SELECT id FROM routing_rules WHERE menu_item_id = $1 LIMIT 1", id)
+	err := tx.GetContext(ctx, &ruleID, "SELECT id FROM routing_rules WHERE menu_item_id = $1 ORDER BY priority ASC LIMIT 1", id)
 	if err == nil {
 		// Update existing rule
-		_, err = tx.Exec(
+		_, err = tx.ExecContext(
+			ctx,
 			"UPDATE routing_rules SET station_id = $1, updated_at = $2 WHERE id = $3",
 			stationID, time.Now(), ruleID,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update routing rule: %w", err)
-		}
-	} else {
-		// Create new rule
-		_, err = tx.Exec(
-			"INSERT INTO routing_rules (menu_item_id, station_id, priority) VALUES ($1, $2, $3)",
-			id, stationID, 1,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create routing rule: %w", err)
+			return fmt.Errorf("failed to update routing rule: %w", err)
 		}
+		return nil
 	}
 
-	// Commit the transaction
-	err = tx.Commit()
+	// Create new rule
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO routing_rules (menu_item_id, station_id, priority) VALUES ($1, $2, $3)",
+		id, stationID, 1,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to create routing rule: %w", err)
 	}
 
-	return r.GetItemByID(ctx, id)
+	return nil
 }
 
 // DeleteItem deletes a menu item
@@ -473,19 +823,92 @@ func (r *MenuRepository) DeleteItem(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// BulkDeleteItems soft-deletes menu items in a single transaction
+// Items referenced by open orders are skipped and reported in the results
+func (r *MenuRepository) BulkDeleteItems(ctx context.Context, ids []uuid.UUID) ([]models.MenuItemDeleteResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	results := make([]models.MenuItemDeleteResult, 0, len(ids))
+
+	for _, id := range ids {
+		// Skip items that are still on open orders
+		var openCount int
+		err = tx.GetContext(
+			ctx,
+			&openCount,
+			`SELECT COUNT(*) FROM order_items oi
+			 JOIN orders o ON oi.order_id = o.id
+			 WHERE oi.menu_item_id = $1 AND o.status IN ($2, $3)`,
+			id, models.OrderStatusNew, models.OrderStatusInProgress,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check open orders for item: %w", err)
+		}
+
+		if openCount > 0 {
+			results = append(results, models.MenuItemDeleteResult{
+				ID:     id,
+				Reason: fmt.Sprintf("referenced by %d open order items", openCount),
+			})
+			continue
+		}
+
+		// Soft-delete the item and take it off sale
+		var result sql.Result
+		result, err = tx.ExecContext(
+			ctx,
+			"UPDATE menu_items SET deleted_at = $1, available = false, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL",
+			now, id,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete menu item: %w", err)
+		}
+
+		var rowsAffected int64
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			results = append(results, models.MenuItemDeleteResult{ID: id, Reason: "menu item not found"})
+			continue
+		}
+
+		results = append(results, models.MenuItemDeleteResult{ID: id, Deleted: true})
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return results, nil
+}
+
 // ListModifiers retrieves all modifiers
 func (r *MenuRepository) ListModifiers(ctx context.Context) ([]models.Modifier, error) {
 	query := `
-		SELECT id, name, is_multiple, created_at, updated_at
+		SELECT id, name, is_multiple, min_selections, max_selections, created_by, updated_by, created_at, updated_at
 		FROM modifiers
 		ORDER BY name ASC
 	`
 
 	var modifiers []models.Modifier
 
-	err := r.db.GetContext(ctx, &modifiers, query)
+	err := r.db.SelectContext(ctx, &modifiers, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get modifier: %w", err)
+		return nil, fmt.Errorf("failed to list modifiers: %w", err)
 	}
 
 	return modifiers, nil
@@ -498,23 +921,89 @@ func (r *MenuRepository) ListModifierWithOptions(ctx context.Context) ([]models.
 		return nil, fmt.Errorf("failed to list modifiers: %w", err)
 	}
 
-	// Get options for modifiers
-	for i := range modifiers {
-		options, err := r.GetModifierOptions(ctx, modifiers[i].ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get modifier options: %w", err)
-		}
-		modifiers[i].Options = options
+	if err := r.loadModifierOptions(ctx, modifiers); err != nil {
+		return nil, err
 	}
 
 	return modifiers, nil
+}
 
+// likeEscaper escapes LIKE wildcards so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchModifiers returns one page of modifiers matching filter, by name, with their options,
+// along with how many match in total
+func (r *MenuRepository) SearchModifiers(ctx context.Context, filter models.ModifierFilter) ([]models.Modifier, int, error) {
+	where := " WHERE name ILIKE '%' || $1 || '%'"
+	args := []interface{}{likeEscaper.Replace(filter.Query)}
+	if filter.Multiple != nil {
+		where += " AND is_multiple = $2"
+		args = append(args, *filter.Multiple)
+	}
+
+	var total int
+	err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM modifiers"+where, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count modifiers: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, name, is_multiple, min_selections, max_selections, created_by, updated_by, created_at, updated_at
+		FROM modifiers%s
+		ORDER BY name ASC, id ASC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	modifiers := []models.Modifier{}
+	if err := r.db.SelectContext(ctx, &modifiers, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to search modifiers: %w", err)
+	}
+
+	if err := r.loadModifierOptions(ctx, modifiers); err != nil {
+		return nil, 0, err
+	}
+
+	return modifiers, total, nil
+}
+
+// loadModifierOptions fills in the options of several modifiers with one query
+func (r *MenuRepository) loadModifierOptions(ctx context.Context, modifiers []models.Modifier) error {
+	if len(modifiers) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(modifiers))
+	for i, m := range modifiers {
+		ids[i] = m.ID
+	}
+
+	query := `
+		SELECT id, modifier_id, name, price_adjustment, adjustment_type, created_at, updated_at
+		FROM modifier_options
+		WHERE modifier_id = ANY($1::uuid[])
+		ORDER BY name ASC
+	`
+
+	var options []models.ModifierOption
+	if err := r.db.SelectContext(ctx, &options, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to get modifier options: %w", err)
+	}
+
+	byModifier := make(map[uuid.UUID][]models.ModifierOption, len(modifiers))
+	for _, o := range options {
+		byModifier[o.ModifierID] = append(byModifier[o.ModifierID], o)
+	}
+	for i := range modifiers {
+		modifiers[i].Options = byModifier[modifiers[i].ID]
+	}
+
+	return nil
 }
 
 // GetModifier retrieves a modifier by ID
 func (r *MenuRepository) GetModifier(ctx context.Context, id uuid.UUID) (*models.Modifier, error) {
 	query := `
-		SELECT id, name, is_multiple, created_at, updated_at
+		SELECT id, name, is_multiple, min_selections, max_selections, created_by, updated_by, created_at, updated_at
 		FROM modifiers
 		WHERE id = $1
 	`
@@ -523,7 +1012,7 @@ func (r *MenuRepository) GetModifier(ctx context.Context, id uuid.UUID) (*models
 
 	err := r.db.GetContext(ctx, &modifier, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get modifier: %w", err)
+		return nil, fmt.Errorf("failed to get modifier: %w", notFound(err))
 	}
 
 	// Get options for this mod
@@ -536,8 +1025,8 @@ func (r *MenuRepository) GetModifier(ctx context.Context, id uuid.UUID) (*models
 	return &modifier, nil
 }
 
-// CreateModifier creates a new modifier
-func (r *MenuRepository) CreateModifier(ctx context.Context, name string, isMultiple bool, options []models.ModifierOption) (*models.Modifier, error) {
+// CreateModifier creates a new modifier created by userID
+func (r *MenuRepository) CreateModifier(ctx context.Context, userID uuid.UUID, modifier models.Modifier, options []models.ModifierOption) (*models.Modifier, error) {
 	// Start a transaction
 	tx, err := r.beginTransaction(ctx)
 	if err != nil {
@@ -549,8 +1038,8 @@ func (r *MenuRepository) CreateModifier(ctx context.Context, name string, isMult
 	err = tx.GetContext(
 		ctx,
 		&modifierID,
-		"INSERT INTO modifiers (name, is_multiple) VALUES ($1, $2) RETURNING id",
-		name, isMultiple,
+		"INSERT INTO modifiers (name, is_multiple, min_selections, max_selections, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $5) RETURNING id",
+		modifier.Name, modifier.IsMultiple, modifier.MinSelections, modifier.MaxSelections, userID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create modifier: %w", err)
@@ -559,8 +1048,8 @@ func (r *MenuRepository) CreateModifier(ctx context.Context, name string, isMult
 	// Add options
 	for _, opt := range options {
 		_, err = tx.Exec(
-			"INSERT INTO modifier_options (modifier_id, name, price_adjustment) VALUES ($1, $2, $3)",
-			modifierID, opt.Name, opt.PriceAdjustment,
+			"INSERT INTO modifier_options (modifier_id, name, price_adjustment, adjustment_type) VALUES ($1, $2, $3, $4)",
+			modifierID, opt.Name, opt.PriceAdjustment, adjustmentTypeOrDefault(opt.AdjustmentType),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to add modifier option: %w", err)
@@ -577,8 +1066,8 @@ func (r *MenuRepository) CreateModifier(ctx context.Context, name string, isMult
 	return r.GetModifier(ctx, modifierID)
 }
 
-// UpdateModifier updates a modifier
-func (r *MenuRepository) UpdateModifier(ctx context.Context, id uuid.UUID, name string, isMultiple bool, options []models.ModifierOption) (*models.Modifier, error) {
+// UpdateModifier updates a modifier, recording userID as the editor
+func (r *MenuRepository) UpdateModifier(ctx context.Context, id, userID uuid.UUID, modifier models.Modifier, options []models.ModifierOption) (*models.Modifier, error) {
 	// Start a transaction
 	tx, err := r.beginTransaction(ctx)
 	if err != nil {
@@ -587,8 +1076,8 @@ func (r *MenuRepository) UpdateModifier(ctx context.Context, id uuid.UUID, name
 
 	// Update the modifier
 	_, err = tx.Exec(
-		"UPDATE modifiers SET name = $1, is_multiple = $2, updated_at = $3 WHERE id = $4",
-		name, isMultiple, time.Now(), id,
+		"UPDATE modifiers SET name = $1, is_multiple = $2, min_selections = $3, max_selections = $4, updated_at = $5, updated_by = $6 WHERE id = $7",
+		modifier.Name, modifier.IsMultiple, modifier.MinSelections, modifier.MaxSelections, time.Now(), userID, id,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update modifier: %w", err)
@@ -603,8 +1092,8 @@ func (r *MenuRepository) UpdateModifier(ctx context.Context, id uuid.UUID, name
 	// Add new options
 	for _, opt := range options {
 		_, err = tx.Exec(
-			"INSERT INTO modifier_options (modifier_id, name, price_adjustment) VALUES ($1, $2, $3)",
-			id, opt.Name, opt.PriceAdjustment,
+			"INSERT INTO modifier_options (modifier_id, name, price_adjustment, adjustment_type) VALUES ($1, $2, $3, $4)",
+			id, opt.Name, opt.PriceAdjustment, adjustmentTypeOrDefault(opt.AdjustmentType),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to add modifier option: %w", err)
@@ -621,6 +1110,125 @@ func (r *MenuRepository) UpdateModifier(ctx context.Context, id uuid.UUID, name
 	return r.GetModifier(ctx, id)
 }
 
+// AdjustPrices applies req to the price of every live menu item in req.CategoryID, or every
+// live item when it is nil, in one transaction on behalf of userID. Each changed price is
+// recorded in the price history.
+func (r *MenuRepository) AdjustPrices(ctx context.Context, userID uuid.UUID, req models.MenuPriceAdjustRequest) ([]models.MenuPriceChange, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := "SELECT id, name, price FROM menu_items WHERE deleted_at IS NULL"
+	var args []interface{}
+	if req.CategoryID != nil {
+		query += " AND category_id = $1"
+		args = append(args, *req.CategoryID)
+	}
+	query += " ORDER BY name ASC, id ASC FOR UPDATE"
+
+	changes := []models.MenuPriceChange{}
+	if err = tx.SelectContext(ctx, &changes, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get menu item prices: %w", err)
+	}
+
+	now := time.Now()
+	for i := range changes {
+		changes[i].NewPrice = req.Apply(changes[i].OldPrice)
+		if models.ToMinor(changes[i].NewPrice) == models.ToMinor(changes[i].OldPrice) {
+			continue
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE menu_items SET price = $1, updated_by = $2, updated_at = $3 WHERE id = $4",
+			changes[i].NewPrice, userID, now, changes[i].MenuItemID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update menu item price: %w", err)
+		}
+
+		err = insertPriceChange(ctx, tx, changes[i].MenuItemID, userID, changes[i].OldPrice, changes[i].NewPrice, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetPriceHistory lists the recorded price changes of a menu item, newest first
+func (r *MenuRepository) GetPriceHistory(ctx context.Context, itemID uuid.UUID) ([]models.MenuItemPriceChange, error) {
+	query := `
+		SELECT id, menu_item_id, old_price, new_price, changed_by, changed_at
+		FROM menu_item_price_history
+		WHERE menu_item_id = $1
+		ORDER BY changed_at DESC, id ASC
+	`
+
+	history := []models.MenuItemPriceChange{}
+	if err := r.db.SelectContext(ctx, &history, query, itemID); err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	return history, nil
+}
+
+// lockItemPrice reads a menu item's price, locking its row until the transaction ends
+func lockItemPrice(ctx context.Context, tx *sqlx.Tx, itemID uuid.UUID) (float64, error) {
+	var price float64
+	err := tx.GetContext(ctx, &price, "SELECT price FROM menu_items WHERE id = $1 FOR UPDATE", itemID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get menu item price: %w", notFound(err))
+	}
+	return price, nil
+}
+
+// insertPriceChange records a price change in the price history when the price actually moved
+func insertPriceChange(ctx context.Context, tx *sqlx.Tx, itemID, userID uuid.UUID, oldPrice, newPrice float64, at time.Time) error {
+	if models.ToMinor(oldPrice) == models.ToMinor(newPrice) {
+		return nil
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO menu_item_price_history (menu_item_id, old_price, new_price, changed_by, changed_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		itemID, oldPrice, newPrice, userID, at,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record price history: %w", err)
+	}
+	return nil
+}
+
+// GetModifierUsage lists the menu items a modifier is attached to, by name
+func (r *MenuRepository) GetModifierUsage(ctx context.Context, modifierID uuid.UUID) ([]models.ModifierUsage, error) {
+	query := `
+		SELECT mi.id AS menu_item_id, mi.name, mim.required, mi.deleted_at
+		FROM menu_item_modifiers mim
+		JOIN menu_items mi ON mim.menu_item_id = mi.id
+		WHERE mim.modifier_id = $1
+		ORDER BY mi.name ASC, mi.id ASC
+	`
+
+	usage := []models.ModifierUsage{}
+	if err := r.db.SelectContext(ctx, &usage, query, modifierID); err != nil {
+		return nil, fmt.Errorf("failed to get modifier usage: %w", err)
+	}
+
+	return usage, nil
+}
+
 // DeleteModifier deletes a modifier
 func (r *MenuRepository) DeleteModifier(ctx context.Context, id uuid.UUID) error {
 	// Check if the modifier is used by any menu items
@@ -665,3 +1273,11 @@ func (r *MenuRepository) DeleteModifier(ctx context.Context, id uuid.UUID) error
 
 	return nil
 }
+
+// adjustmentTypeOrDefault treats an unset adjustment type as absolute
+func adjustmentTypeOrDefault(t models.AdjustmentType) models.AdjustmentType {
+	if t == "" {
+		return models.AdjustmentTypeAbsolute
+	}
+	return t
+}
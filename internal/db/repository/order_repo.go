@@ -2,29 +2,58 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pizza-nz/restaurant-service/internal/models"
 )
 
+var (
+	// ErrInvalidQuantity is returned when an item quantity doesn't suit the menu item's unit
+	ErrInvalidQuantity = errors.New("invalid quantity")
+
+	// ErrNoStationConfigured is returned when an ordered item can't be routed to any active station
+	ErrNoStationConfigured = errors.New("no station configured")
+
+	// ErrDuplicateExternalOrder is returned when a platform's order has already been received
+	ErrDuplicateExternalOrder = errors.New("order already received")
+
+	// ErrOrderStatusChanged is returned when an order's status changed after it was read
+	ErrOrderStatusChanged = errors.New("order status changed, reload and try again")
+
+	// ErrItemStatusChanged is returned when an order item's status changed after it was read
+	ErrItemStatusChanged = errors.New("order item status changed, reload and try again")
+)
+
 // OrderRepository handles order data access
 type OrderRepository struct {
 	db *sqlx.DB
+
+	// autoComplete completes an order when its last item is completed,
+	// otherwise the order is left ready for an explicit close
+	autoComplete bool
 }
 
 // NewOrderRepository creates a new order repository
 func NewOrderRepository(db *sqlx.DB) *OrderRepository {
-	return &OrderRepository{db: db}
+	return &OrderRepository{db: db, autoComplete: true}
+}
+
+// SetAutoComplete sets whether completing an order's last item completes the order
+func (r *OrderRepository) SetAutoComplete(enabled bool) {
+	r.autoComplete = enabled
 }
 
 // GetByID retrieves an order by ID
 func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	query := `
-		SELECT id, user_id, order_number, status, total, ordered_at, completed_at, created_at, updated_at
+		SELECT id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
 		FROM orders
 		WHERE id = $1
 	`
@@ -32,7 +61,7 @@ func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Or
 	var order models.Order
 	err := r.db.GetContext(ctx, &order, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order: %w", err)
+		return nil, fmt.Errorf("failed to get order: %w", notFound(err))
 	}
 
 	// Get order items
@@ -41,6 +70,7 @@ func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Or
 		return nil, fmt.Errorf("failed to get order items: %w", err)
 	}
 	order.Items = items
+	order.SetProgress()
 
 	return &order, nil
 }
@@ -49,7 +79,7 @@ func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Or
 func (r *OrderRepository) GetOrderItems(ctx context.Context, orderID uuid.UUID) ([]models.OrderItem, error) {
 	query := `
 		SELECT oi.id, oi.order_id, oi.menu_item_id, oi.station_id, oi.quantity, oi.price,
-		       oi.status, oi.special_instructions, oi.sent_to_station_at, oi.completed_at, 
+		       oi.status, oi.special_instructions, oi.seat, oi.sent_to_station_at, oi.completed_at, 
 		       oi.created_at, oi.updated_at, 
 		       mi.name as name
 		FROM order_items oi
@@ -79,11 +109,9 @@ func (r *OrderRepository) GetOrderItems(ctx context.Context, orderID uuid.UUID)
 // GetOrderItemModifiers retrieves modifiers for an order item
 func (r *OrderRepository) GetOrderItemModifiers(ctx context.Context, orderItemID uuid.UUID) ([]models.OrderItemModifier, error) {
 	query := `
-		SELECT oim.id, oim.order_item_id, oim.modifier_option_id, oim.price_adjustment, oim.created_at,
-		       mo.name as name
-		FROM order_item_modifiers oim
-		JOIN modifier_options mo ON oim.modifier_option_id = mo.id
-		WHERE oim.order_item_id = $1
+		SELECT id, order_item_id, modifier_option_id, name, price_adjustment, created_at
+		FROM order_item_modifiers
+		WHERE order_item_id = $1
 	`
 
 	var modifiers []models.OrderItemModifier
@@ -102,7 +130,7 @@ func (r *OrderRepository) List(ctx context.Context, status *models.OrderStatus)
 
 	if status != nil {
 		query = `
-			SELECT id, user_id, order_number, status, total, ordered_at, completed_at, created_at, updated_at
+			SELECT id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
 			FROM orders
 			WHERE status = $1
 			ORDER BY ordered_at DESC
@@ -110,7 +138,7 @@ func (r *OrderRepository) List(ctx context.Context, status *models.OrderStatus)
 		args = append(args, *status)
 	} else {
 		query = `
-			SELECT id, user_id, order_number, status, total, ordered_at, completed_at, created_at, updated_at
+			SELECT id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
 			FROM orders
 			ORDER BY ordered_at DESC
 		`
@@ -128,6 +156,76 @@ func (r *OrderRepository) List(ctx context.Context, status *models.OrderStatus)
 	return orders, nil
 }
 
+// GetByExternalID retrieves the order a platform sent with its own order ID
+func (r *OrderRepository) GetByExternalID(ctx context.Context, provider, externalID string) (*models.Order, error) {
+	var id uuid.UUID
+	err := r.db.GetContext(
+		ctx,
+		&id,
+		"SELECT id FROM orders WHERE provider = $1 AND external_id = $2",
+		provider, externalID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order by external ID: %w", notFound(err))
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// SearchByOrderNumber returns up to limit orders placed since the given time whose order number
+// contains fragment, those ending with it first and then newest first. fragment must not
+// contain LIKE wildcards.
+func (r *OrderRepository) SearchByOrderNumber(ctx context.Context, fragment string, since time.Time, limit int) ([]models.Order, error) {
+	query := `
+		SELECT id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
+		FROM orders
+		WHERE order_number LIKE '%' || $1 || '%' AND ordered_at >= $2
+		ORDER BY order_number LIKE '%' || $1 DESC, ordered_at DESC
+		LIMIT $3
+	`
+
+	orders := []models.Order{}
+	err := r.db.SelectContext(ctx, &orders, query, fragment, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// ListOpen returns one page of orders that are neither completed nor cancelled,
+// along with how many there are in total
+func (r *OrderRepository) ListOpen(ctx context.Context, filter models.OpenOrderFilter) ([]models.Order, int, error) {
+	where := " WHERE status NOT IN ($1, $2)"
+	args := []interface{}{models.OrderStatusCompleted, models.OrderStatusCancelled}
+
+	var total int
+	err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM orders"+where, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count open orders: %w", err)
+	}
+
+	orderBy := "ordered_at ASC, id ASC"
+	if filter.Sort == models.OpenOrderSortNewest {
+		orderBy = "ordered_at DESC, id DESC"
+	}
+
+	query := `
+		SELECT id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
+		FROM orders` + where + `
+		ORDER BY ` + orderBy + `
+		LIMIT $3 OFFSET $4`
+	args = append(args, filter.Limit, filter.Offset)
+
+	orders := []models.Order{}
+	err = r.db.SelectContext(ctx, &orders, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
 // Create creates a new order with its items
 func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRequests []models.OrderItemRequest) (*models.Order, error) {
 	// Start a transaction
@@ -143,9 +241,9 @@ func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRe
 
 	// Insert the order
 	orderQuery := `
-		INSERT INTO orders (user_id, order_number, status, total, ordered_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, user_id, order_number, status, total, ordered_at, completed_at, created_at, updated_at
+		INSERT INTO orders (user_id, order_number, status, total, notes, ordered_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $1, $1)
+		RETURNING id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
 	`
 
 	var createdOrder models.Order
@@ -157,40 +255,52 @@ func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRe
 		order.OrderNumber,
 		order.Status,
 		order.Total,
+		order.Notes,
 		order.OrderedAt,
+		order.CustomerName,
+		order.CustomerPhone,
+		order.Source,
+		order.Provider,
+		order.ExternalID,
 	)
+	// NULLs never collide in the index, so both are set when it's violated
+	if isUniqueViolationOf(err, "idx_orders_provider_external_id") {
+		return nil, fmt.Errorf("%w: %s %s", ErrDuplicateExternalOrder, *order.Provider, *order.ExternalID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
 	// Insert each order item
-	createdOrder.Items = make([]models.OrderItem, 0, len(itemRequests))
-
 	for _, itemReq := range itemRequests {
 		// Get the menu item to determine routing
 		var menuItem struct {
-			Name string `db:"name"`
+			Name string              `db:"name"`
+			Unit models.MenuItemUnit `db:"unit"`
 		}
 		err = tx.GetContext(
 			ctx,
 			&menuItem,
-			"SELECT name FROM menu_items WHERE id = $1",
+			"SELECT name, unit FROM menu_items WHERE id = $1 AND deleted_at IS NULL",
 			itemReq.MenuItemID,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get menu item: %w", err)
+			return nil, fmt.Errorf("failed to get menu item: %w", notFound(err))
+		}
+
+		if !menuItem.Unit.AllowsQuantity(itemReq.Quantity) {
+			err = fmt.Errorf("%w: %v %s of %s", ErrInvalidQuantity, itemReq.Quantity, menuItem.Unit, menuItem.Name)
+			return nil, err
 		}
 
-		// Get the routing station
 		var stationID uuid.UUID
-		err = tx.GetContext(
-			ctx,
-			&stationID,
-			`SELECT station_id FROM routing_rules WHERE menu_item_id = $1 ORDER BY priority ASC LIMIT 1`,
-			itemReq.MenuItemID,
-		)
+		stationID, err = resolveStation(ctx, tx, itemReq.MenuItemID)
+		if errors.Is(err, ErrNoStationConfigured) {
+			err = fmt.Errorf("%w for %s", ErrNoStationConfigured, menuItem.Name)
+			return nil, err
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to get routing station: %w", err)
+			return nil, err
 		}
 
 		// Insert the order item
@@ -199,10 +309,10 @@ func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRe
 			ctx,
 			&createdItem,
 			`INSERT INTO order_items 
-			 (order_id, menu_item_id, station_id, quantity, price, status, special_instructions)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7)
+			 (order_id, menu_item_id, station_id, quantity, price, status, special_instructions, seat)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			 RETURNING id, order_id, menu_item_id, station_id, quantity, price, status, 
-			          special_instructions, sent_to_station_at, completed_at, created_at, updated_at`,
+			          special_instructions, seat, sent_to_station_at, completed_at, created_at, updated_at`,
 			createdOrder.ID,
 			itemReq.MenuItemID,
 			stationID,
@@ -210,14 +320,12 @@ func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRe
 			0.0, // We'll calculate the price after adding modifiers
 			models.OrderItemStatusPending,
 			itemReq.SpecialInstructions,
+			itemReq.Seat,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create order item: %w", err)
 		}
 
-		// Set the item name from the menu item
-		createdItem.Name = menuItem.Name
-
 		// Get the base price from the menu item
 		var basePrice float64
 		err = tx.GetContext(
@@ -235,46 +343,37 @@ func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRe
 
 		// Add modifiers if any
 		if len(itemReq.Modifiers) > 0 {
-			createdItem.Modifiers = make([]models.OrderItemModifier, 0, len(itemReq.Modifiers))
-
 			for _, mod := range itemReq.Modifiers {
 				// Get the modifier option details
-				var option struct {
-					Name            string  `db:"name"`
-					PriceAdjustment float64 `db:"price_adjustment"`
-				}
+				var option models.ModifierOption
 				err = tx.GetContext(
 					ctx,
 					&option,
-					"SELECT name, price_adjustment FROM modifier_options WHERE id = $1",
+					"SELECT name, price_adjustment, adjustment_type FROM modifier_options WHERE id = $1",
 					mod.OptionID,
 				)
 				if err != nil {
-					return nil, fmt.Errorf("failed to get modifier option: %w", err)
+					return nil, fmt.Errorf("failed to get modifier option: %w", notFound(err))
 				}
 
-				// Add the price adjustment
-				price += option.PriceAdjustment
+				// Add the price adjustment, percentages scale with the base price
+				adjustment := option.AdjustmentFor(basePrice)
+				price = models.AddMoney(price, adjustment)
 
 				// Insert the order item modifier
-				var createdMod models.OrderItemModifier
-				err = tx.GetContext(
+				_, err = tx.ExecContext(
 					ctx,
-					&createdMod,
 					`INSERT INTO order_item_modifiers 
-					 (order_item_id, modifier_option_id, price_adjustment)
-					 VALUES ($1, $2, $3)
-					 RETURNING id, order_item_id, modifier_option_id, price_adjustment, created_at`,
+					 (order_item_id, modifier_option_id, name, price_adjustment)
+					 VALUES ($1, $2, $3, $4)`,
 					createdItem.ID,
 					mod.OptionID,
-					option.PriceAdjustment,
+					option.Name,
+					adjustment,
 				)
 				if err != nil {
 					return nil, fmt.Errorf("failed to create order item modifier: %w", err)
 				}
-
-				createdMod.Name = option.Name
-				createdItem.Modifiers = append(createdItem.Modifiers, createdMod)
 			}
 		}
 
@@ -289,11 +388,8 @@ func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRe
 			return nil, fmt.Errorf("failed to update order item price: %w", err)
 		}
 
-		createdItem.Price = price
-		createdOrder.Items = append(createdOrder.Items, createdItem)
-
-		// Update order total
-		createdOrder.Total += price * float64(createdItem.Quantity)
+		// Update order total, summed in minor units so many small items don't drift
+		createdOrder.Total = models.AddMoney(createdOrder.Total, models.LineTotal(price, createdItem.Quantity))
 	}
 
 	// Update the order total
@@ -313,7 +409,150 @@ func (r *OrderRepository) Create(ctx context.Context, order models.Order, itemRe
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return &createdOrder, nil
+	// Re-read so the response has the same shape as any later fetch
+	return r.GetByID(ctx, createdOrder.ID)
+}
+
+// RecalculateTotal re-sums an order's non-cancelled lines and stores the result
+// The order row is locked so concurrent recalculations apply one at a time
+func (r *OrderRepository) RecalculateTotal(ctx context.Context, id, userID uuid.UUID) (previous, total float64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = tx.GetContext(ctx, &previous, "SELECT total FROM orders WHERE id = $1 FOR UPDATE", id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get order total: %w", notFound(err))
+	}
+
+	err = tx.GetContext(
+		ctx,
+		&total,
+		"SELECT COALESCE(SUM(ROUND(price * quantity, 2)), 0) FROM order_items WHERE order_id = $1 AND status <> $2",
+		id,
+		models.OrderItemStatusCancelled,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum order items: %w", err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE orders SET total = $1, updated_at = $2, updated_by = $3 WHERE id = $4",
+		total,
+		time.Now(),
+		userID,
+		id,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to update order total: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return previous, total, nil
+}
+
+// UpdateNotes updates the order-wide notes, recording userID as the editor
+func (r *OrderRepository) UpdateNotes(ctx context.Context, id, userID uuid.UUID, notes *string) error {
+	query := `
+		UPDATE orders
+		SET notes = $1, updated_at = $2, updated_by = $3
+		WHERE id = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, notes, time.Now(), userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to update order notes: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("order %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// ResolveStation returns the station a menu item would be routed to if ordered now
+func (r *OrderRepository) ResolveStation(ctx context.Context, menuItemID uuid.UUID) (uuid.UUID, error) {
+	return resolveStation(ctx, r.db, menuItemID)
+}
+
+// resolveStation gets the highest priority active routing station, falling back to the
+// category's default station when the item has no usable routing rule
+func resolveStation(ctx context.Context, q sqlx.QueryerContext, menuItemID uuid.UUID) (uuid.UUID, error) {
+	var stationID uuid.UUID
+	err := sqlx.GetContext(
+		ctx,
+		q,
+		&stationID,
+		`SELECT rr.station_id FROM routing_rules rr
+		 JOIN stations s ON rr.station_id = s.id
+		 WHERE rr.menu_item_id = $1 AND s.is_active = true
+		 ORDER BY rr.priority ASC LIMIT 1`,
+		menuItemID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = sqlx.GetContext(
+			ctx,
+			q,
+			&stationID,
+			`SELECT s.id FROM menu_items mi
+			 JOIN menu_categories mc ON mi.category_id = mc.id
+			 JOIN stations s ON mc.default_station_id = s.id
+			 WHERE mi.id = $1 AND s.is_active = true`,
+			menuItemID,
+		)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, ErrNoStationConfigured
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get routing station: %w", err)
+	}
+
+	return stationID, nil
+}
+
+// NextOrderSequence atomically increments and returns the order counter for a day
+func (r *OrderRepository) NextOrderSequence(ctx context.Context, day time.Time) (int, error) {
+	query := `
+		INSERT INTO order_number_sequences (business_date, last_value)
+		VALUES ($1, 1)
+		ON CONFLICT (business_date) DO UPDATE SET last_value = order_number_sequences.last_value + 1
+		RETURNING last_value
+	`
+
+	var seq int
+	err := r.db.GetContext(ctx, &seq, query, day.Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next order sequence: %w", err)
+	}
+
+	return seq, nil
+}
+
+// NextContinuousOrderSequence returns the next value of the order counter that never resets
+func (r *OrderRepository) NextContinuousOrderSequence(ctx context.Context) (int, error) {
+	var seq int
+	if err := r.db.GetContext(ctx, &seq, "SELECT nextval('order_number_seq')"); err != nil {
+		return 0, fmt.Errorf("failed to get next order sequence: %w", err)
+	}
+
+	return seq, nil
 }
 
 // UpdateStatus updates an order's status
@@ -325,13 +564,13 @@ func (r *OrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 
 	args := []interface{}{status, time.Now()}
 
-	// If the status is completed, set the completed_at timestamp
+	// Set completed_at when completing, and clear it when leaving completed
 	if status == models.OrderStatusCompleted {
 		query += ", completed_at = $3 WHERE id = $4"
 		now := time.Now()
 		args = append(args, now, id)
 	} else {
-		query += " WHERE id = $3"
+		query += ", completed_at = NULL WHERE id = $3"
 		args = append(args, id)
 	}
 
@@ -348,156 +587,151 @@ func (r *OrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("order not found")
+		return fmt.Errorf("order %w", ErrNotFound)
 	}
 
 	return nil
 }
 
-// UpdateItemStatus updates an order item's status
-func (r *OrderRepository) UpdateItemStatus(ctx context.Context, itemID uuid.UUID, status models.OrderItemStatus) error {
-	query := `
-		UPDATE order_items
-		SET status = $1, updated_at = $2
-	`
+// TransitionStatus moves an order from one status to another in one transaction, failing with
+// ErrOrderStatusChanged if it is no longer in from. Cancelling also cancels its unfinished items.
+func (r *OrderRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to models.OrderStatus) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
 
-	args := []interface{}{status, time.Now()}
+	now := time.Now()
 
-	// If the status is completed, set the completed_at timestamp
-	if status == models.OrderItemStatusCompleted {
-		query += ", completed_at = $3 WHERE id = $4"
-		now := time.Now()
-		args = append(args, now, itemID)
-	} else if status == models.OrderItemStatusInProgress {
-		// If the item is now in progress and wasn't sent to a station yet,
-		// set the sent_to_station_at timestamp
-		query += ", sent_to_station_at = CASE WHEN sent_to_station_at IS NULL THEN $3 ELSE sent_to_station_at END WHERE id = $4"
-		now := time.Now()
-		args = append(args, now, itemID)
-	} else {
-		query += " WHERE id = $3"
-		args = append(args, itemID)
+	var completedAt *time.Time
+	if to == models.OrderStatusCompleted {
+		completedAt = &now
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE orders SET status = $1, completed_at = $2, updated_at = $3 WHERE id = $4 AND status = $5",
+		to,
+		completedAt,
+		now,
+		id,
+		from,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to update order item status: %w", err)
+		return fmt.Errorf("failed to update order status: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
-
 	if rowsAffected == 0 {
-		return errors.New("order item not found")
+		err = ErrOrderStatusChanged
+		return err
 	}
 
-	// Check if all items in the order are completed and update order status if needed
-	if status == models.OrderItemStatusCompleted {
-		// Get the order ID for this item
-		var orderID uuid.UUID
-		err = r.db.GetContext(
-			ctx,
-			&orderID,
-			"SELECT order_id FROM order_items WHERE id = $1",
-			itemID,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to get order ID for item: %w", err)
-		}
-
-		// Check if all items in the order are completed
-		var pendingCount int
-		err = r.db.GetContext(
+	if to == models.OrderStatusCancelled {
+		_, err = tx.ExecContext(
 			ctx,
-			&pendingCount,
-			`SELECT COUNT(*) FROM order_items 
-			 WHERE order_id = $1 AND status != $2`,
-			orderID, models.OrderItemStatusCompleted,
+			"UPDATE order_items SET status = $1, updated_at = $2 WHERE order_id = $3 AND status IN ($4, $5, $6)",
+			models.OrderItemStatusCancelled,
+			now,
+			id,
+			models.OrderItemStatusPending,
+			models.OrderItemStatusInProgress,
+			models.OrderItemStatusReady,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to check pending items: %w", err)
+			return fmt.Errorf("failed to cancel order items: %w", err)
 		}
+	}
 
-		// If no pending items, mark the order as completed
-		if pendingCount == 0 {
-			err = r.UpdateStatus(ctx, orderID, models.OrderStatusCompleted)
-			if err != nil {
-				return fmt.Errorf("failed to update order status: %w", err)
-			}
-		}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-// GetStationItems gets all pending and in-progress items for a station
-func (r *OrderRepository) GetStationItems(ctx context.Context, stationID uuid.UUID) ([]models.OrderItem, error) {
-	query := `
-		SELECT oi.id, oi.order_id, oi.menu_item_id, oi.station_id, oi.quantity, oi.price,
-		       oi.status, oi.special_instructions, oi.sent_to_station_at, oi.completed_at, 
-		       oi.created_at, oi.updated_at, 
-		       mi.name as name,
-		       o.order_number
-		FROM order_items oi
-		JOIN menu_items mi ON oi.menu_item_id = mi.id
-		JOIN orders o ON oi.order_id = o.id
-		WHERE oi.station_id = $1 
-		  AND oi.status IN ($2, $3)
-		  AND o.status IN ($4, $5)
-		ORDER BY oi.sent_to_station_at ASC NULLS FIRST, oi.created_at ASC
-	`
+// SetRush flags or unflags an open order as rushed
+func (r *OrderRepository) SetRush(ctx context.Context, id uuid.UUID, rush bool) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		"UPDATE orders SET is_rush = $1, updated_at = $2 WHERE id = $3",
+		rush, time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set order rush: %w", err)
+	}
 
-	var items []models.OrderItem
-	err := r.db.SelectContext(
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("order %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// SetItemRush flags or unflags an order item as rushed, on top of any rush on its order
+func (r *OrderRepository) SetItemRush(ctx context.Context, itemID uuid.UUID, rush bool) error {
+	result, err := r.db.ExecContext(
 		ctx,
-		&items,
-		query,
-		stationID,
-		models.OrderItemStatusPending,
-		models.OrderItemStatusInProgress,
-		models.OrderStatusNew,
-		models.OrderStatusInProgress,
+		"UPDATE order_items SET is_rush = $1, updated_at = $2 WHERE id = $3",
+		rush, time.Now(), itemID,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get station items: %w", err)
+		return fmt.Errorf("failed to set order item rush: %w", err)
 	}
 
-	// For each item, get modifiers
-	for i := range items {
-		modifiers, err := r.GetOrderItemModifiers(ctx, items[i].ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get item modifiers: %w", err)
-		}
-		items[i].Modifiers = modifiers
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	return items, nil
+	if rowsAffected == 0 {
+		return fmt.Errorf("order item %w", ErrNotFound)
+	}
+
+	return nil
 }
 
-// GetOrderHistory gets order history for a specified time range
-func (r *OrderRepository) GetOrderHistory(ctx context.Context, startDate, endDate time.Time) ([]models.Order, error) {
-	query := `
-		SELECT id, user_id, order_number, status, total, ordered_at, completed_at, created_at, updated_at
-		FROM orders
-		WHERE ordered_at BETWEEN $1 AND $2
-		ORDER BY ordered_at DESC
-		LIMIT 500
-	`
+// SetHold puts an order on hold, or releases the hold, recording when
+func (r *OrderRepository) SetHold(ctx context.Context, id uuid.UUID, hold bool) error {
+	query := "UPDATE orders SET held_at = $1, released_at = NULL, updated_at = $1 WHERE id = $2"
+	if !hold {
+		query = "UPDATE orders SET released_at = $1, updated_at = $1 WHERE id = $2"
+	}
 
-	var orders []models.Order
-	err := r.db.SelectContext(ctx, &orders, query, startDate, endDate)
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order history: %w", err)
+		return fmt.Errorf("failed to set order hold: %w", err)
 	}
 
-	return orders, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("order %w", ErrNotFound)
+	}
+
+	return nil
 }
 
-// VoidItem voids an order item
-func (r *OrderRepository) VoidItem(ctx context.Context, itemID uuid.UUID, reason string) error {
-	// Start a transaction
+// UpdateItemStatus moves an order item from one status to another, failing with
+// ErrItemStatusChanged if it is no longer in from. The order is completed in the same
+// transaction when that finishes its last item.
+func (r *OrderRepository) UpdateItemStatus(ctx context.Context, itemID uuid.UUID, from, status models.OrderItemStatus) (err error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -508,42 +742,542 @@ func (r *OrderRepository) VoidItem(ctx context.Context, itemID uuid.UUID, reason
 		}
 	}()
 
-	// Update the item status to cancelled
-	_, err = tx.ExecContext(
-		ctx,
-		`UPDATE order_items 
-		 SET status = $1, updated_at = $2, special_instructions = COALESCE(special_instructions, '') || E'\n[VOIDED: ' || $3 || ']'
-		 WHERE id = $4`,
-		models.OrderItemStatusCancelled,
-		time.Now(),
-		reason,
-		itemID,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to void order item: %w", err)
-	}
+	query := `
+		UPDATE order_items
+		SET status = $1, updated_at = $2
+	`
 
-	// Get order ID and item price/quantity
-	var orderInfo struct {
-		OrderID  uuid.UUID `db:"order_id"`
-		Price    float64   `db:"price"`
-		Quantity int       `db:"quantity"`
+	now := time.Now()
+	args := []interface{}{status, now}
+
+	// If the status is completed, set the completed_at timestamp
+	if status == models.OrderItemStatusCompleted {
+		query += ", completed_at = $3 WHERE id = $4 AND status = $5"
+		args = append(args, now, itemID, from)
+	} else if status == models.OrderItemStatusInProgress {
+		// If the item is now in progress and wasn't sent to a station yet,
+		// set the sent_to_station_at timestamp
+		query += ", sent_to_station_at = CASE WHEN sent_to_station_at IS NULL THEN $3 ELSE sent_to_station_at END WHERE id = $4 AND status = $5"
+		args = append(args, now, itemID, from)
+	} else {
+		query += " WHERE id = $3 AND status = $4"
+		args = append(args, itemID, from)
+	}
+	query += " RETURNING order_id"
+
+	var orderID uuid.UUID
+	err = tx.GetContext(ctx, &orderID, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = itemNotUpdated(ctx, tx, itemID)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update order item status: %w", err)
+	}
+
+	// The first item a station starts on fires the order
+	if status == models.OrderItemStatusInProgress {
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE orders SET fired_at = $1 WHERE id = $2 AND fired_at IS NULL",
+			now, orderID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to set order fired time: %w", err)
+		}
+	}
+
+	if status == models.OrderItemStatusCompleted {
+		err = r.completeIfItemsDone(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// itemNotUpdated explains why a guarded update of an order item touched no rows, the item
+// is either gone or no longer in the status it was read in
+func itemNotUpdated(ctx context.Context, tx *sqlx.Tx, itemID uuid.UUID) error {
+	var exists bool
+	err := tx.GetContext(ctx, &exists, "SELECT EXISTS (SELECT 1 FROM order_items WHERE id = $1)", itemID)
+	if err != nil {
+		return fmt.Errorf("failed to check order item: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("order item %w", ErrNotFound)
+	}
+	return ErrItemStatusChanged
+}
+
+// completeIfItemsDone completes an open order, or marks it ready when orders are closed
+// explicitly, once its items are done, see models.ItemsDone
+func (r *OrderRepository) completeIfItemsDone(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) error {
+	var statuses []models.OrderItemStatus
+	err := tx.SelectContext(ctx, &statuses, "SELECT status FROM order_items WHERE order_id = $1", orderID)
+	if err != nil {
+		return fmt.Errorf("failed to check pending items: %w", err)
+	}
+	if !models.ItemsDone(statuses) {
+		return nil
+	}
+
+	next := models.OrderStatusCompleted
+	if !r.autoComplete {
+		next = models.OrderStatusReady
+	}
+
+	var completedAt *time.Time
+	now := time.Now()
+	if next == models.OrderStatusCompleted {
+		completedAt = &now
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE orders SET status = $1, completed_at = $2, updated_at = $3 WHERE id = $4 AND status IN ($5, $6, $7)",
+		next,
+		completedAt,
+		now,
+		orderID,
+		models.OrderStatusNew,
+		models.OrderStatusInProgress,
+		models.OrderStatusReady,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrderItem retrieves a single order item
+func (r *OrderRepository) GetOrderItem(ctx context.Context, itemID uuid.UUID) (*models.OrderItem, error) {
+	query := `
+		SELECT oi.id, oi.order_id, oi.menu_item_id, oi.station_id, oi.quantity, oi.price,
+		       oi.status, oi.special_instructions, oi.seat, oi.sent_to_station_at, oi.completed_at,
+		       oi.created_at, oi.updated_at,
+		       mi.name as name,
+		       o.order_number, (o.is_rush OR oi.is_rush) AS is_rush
+		FROM order_items oi
+		JOIN menu_items mi ON oi.menu_item_id = mi.id
+		JOIN orders o ON oi.order_id = o.id
+		WHERE oi.id = $1
+	`
+
+	var item models.OrderItem
+	err := r.db.GetContext(ctx, &item, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order item: %w", notFound(err))
+	}
+
+	return &item, nil
+}
+
+// GetLastCompletedStationItem retrieves the item most recently completed at a station
+func (r *OrderRepository) GetLastCompletedStationItem(ctx context.Context, stationID uuid.UUID) (*models.OrderItem, error) {
+	var itemID uuid.UUID
+	err := r.db.GetContext(
+		ctx,
+		&itemID,
+		`SELECT oi.id FROM order_items oi
+		 JOIN orders o ON oi.order_id = o.id
+		 WHERE oi.station_id = $1 AND oi.status = $2 AND o.status <> $3
+		 ORDER BY oi.completed_at DESC NULLS LAST
+		 LIMIT 1`,
+		stationID,
+		models.OrderItemStatusCompleted,
+		models.OrderStatusCancelled,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last completed item: %w", notFound(err))
+	}
+
+	return r.GetOrderItem(ctx, itemID)
+}
+
+// RecallItem moves a ready or completed item back to in progress, reopening its order if needed
+func (r *OrderRepository) RecallItem(ctx context.Context, itemID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+
+	var orderID uuid.UUID
+	err = tx.GetContext(
+		ctx,
+		&orderID,
+		`UPDATE order_items
+		 SET status = $1, completed_at = NULL, updated_at = $2
+		 WHERE id = $3 AND status IN ($4, $5)
+		 RETURNING order_id`,
+		models.OrderItemStatusInProgress,
+		now,
+		itemID,
+		models.OrderItemStatusReady,
+		models.OrderItemStatusCompleted,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to recall order item: %w", err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE orders SET status = $1, completed_at = NULL, updated_at = $2 WHERE id = $3 AND status IN ($4, $5)",
+		models.OrderStatusInProgress,
+		now,
+		orderID,
+		models.OrderStatusReady,
+		models.OrderStatusCompleted,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reopen order: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReopenOrder moves a completed order back to in progress on behalf of userID. The items
+// completed last, by the bump that closed the order, go back to ready. The reason is
+// recorded in the audit log.
+func (r *OrderRepository) ReopenOrder(ctx context.Context, id, userID uuid.UUID, reason string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE orders
+		 SET status = $1, completed_at = NULL, updated_by = $2, updated_at = $3
+		 WHERE id = $4 AND status = $5`,
+		models.OrderStatusInProgress,
+		userID,
+		now,
+		id,
+		models.OrderStatusCompleted,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reopen order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		err = ErrOrderStatusChanged
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE order_items
+		 SET status = $1, completed_at = NULL, updated_at = $2
+		 WHERE order_id = $3 AND status = $4 AND completed_at = (
+			SELECT MAX(completed_at) FROM order_items WHERE order_id = $3 AND status = $4
+		 )`,
+		models.OrderItemStatusReady,
+		now,
+		id,
+		models.OrderItemStatusCompleted,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reopen order items: %w", err)
+	}
+
+	newValues, err := json.Marshal(map[string]string{
+		"status": string(models.OrderStatusInProgress),
+		"reason": reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit values: %w", err)
+	}
+
+	err = insertAuditLog(ctx, tx, models.AuditLog{
+		UserID:    &userID,
+		Action:    "reopen",
+		TableName: "orders",
+		RecordID:  id,
+		NewValues: newValues,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// rushStarvationLimit is how long an item can wait before it is ranked alongside rushed items
+const rushStarvationLimit = 15 * time.Minute
+
+// GetStationItems gets all pending, in-progress and ready items for a station
+// Items a cook placed by hand come first in that order, the rest are ordered by time sent,
+// or kept together per order and seat when grouping by order
+func (r *OrderRepository) GetStationItems(ctx context.Context, stationID uuid.UUID, groupBy models.StationItemsGroupBy) ([]models.OrderItem, error) {
+	return r.GetItemsForStations(ctx, []uuid.UUID{stationID}, groupBy)
+}
+
+// GetItemsForStations gets the station feed items of several stations as one feed,
+// ordered the same way as GetStationItems with the item ID breaking ties
+func (r *OrderRepository) GetItemsForStations(ctx context.Context, stationIDs []uuid.UUID, groupBy models.StationItemsGroupBy) ([]models.OrderItem, error) {
+	query := `
+		SELECT oi.id, oi.order_id, oi.menu_item_id, oi.station_id, oi.quantity, oi.price,
+		       oi.status, oi.special_instructions, oi.seat, oi.sent_to_station_at, oi.completed_at, 
+		       oi.created_at, oi.updated_at, 
+		       mi.name as name, mi.kitchen_notes, oi.display_priority,
+		       o.order_number, (o.is_rush OR oi.is_rush) AS is_rush
+		FROM order_items oi
+		JOIN menu_items mi ON oi.menu_item_id = mi.id
+		JOIN orders o ON oi.order_id = o.id
+		WHERE oi.station_id = ANY($1::uuid[])
+		  AND oi.status IN ($2, $3, $4)
+		  AND o.status IN ($5, $6)
+		  AND (o.held_at IS NULL OR o.released_at IS NOT NULL)
+		ORDER BY ` + stationFeedOrder(groupBy)
+
+	var items []models.OrderItem
+	err := r.db.SelectContext(
+		ctx,
+		&items,
+		query,
+		pq.Array(stationIDs),
+		models.OrderItemStatusPending,
+		models.OrderItemStatusInProgress,
+		models.OrderItemStatusReady,
+		models.OrderStatusNew,
+		models.OrderStatusInProgress,
+		time.Now().Add(-rushStarvationLimit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get station items: %w", err)
+	}
+
+	// For each item, get modifiers
+	for i := range items {
+		modifiers, err := r.GetOrderItemModifiers(ctx, items[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item modifiers: %w", err)
+		}
+		items[i].Modifiers = modifiers
+	}
+
+	return items, nil
+}
+
+// stationFeedOrder is the ORDER BY of a station feed: items a cook placed first, then rush
+// and starved orders, then by time sent or by order and seat. The item ID comes last so a
+// feed merged from several stations has one fixed order.
+func stationFeedOrder(groupBy models.StationItemsGroupBy) string {
+	orderBy := "oi.display_priority ASC NULLS LAST, (o.is_rush OR oi.is_rush OR o.ordered_at < $7) DESC, "
+	if groupBy == models.StationItemsGroupByOrder {
+		return orderBy + "o.ordered_at ASC, o.id, oi.seat ASC NULLS LAST, oi.created_at ASC, oi.id"
+	}
+	return orderBy + "oi.sent_to_station_at ASC NULLS FIRST, oi.created_at ASC, oi.id"
+}
+
+// GetKitchenItems gets every live item of the orders being cooked in one query, oldest order
+// first with each order's items kept together by station. Held orders are left out.
+func (r *OrderRepository) GetKitchenItems(ctx context.Context) ([]models.KitchenItem, error) {
+	query := `
+		SELECT oi.id, oi.order_id, oi.menu_item_id, oi.station_id, oi.quantity, oi.price,
+		       oi.status, oi.special_instructions, oi.seat, oi.sent_to_station_at, oi.completed_at,
+		       oi.created_at, oi.updated_at,
+		       mi.name as name, mi.kitchen_notes,
+		       o.order_number, (o.is_rush OR oi.is_rush) AS is_rush, o.status AS order_status, o.ordered_at,
+		       s.name AS station_name
+		FROM order_items oi
+		JOIN menu_items mi ON oi.menu_item_id = mi.id
+		JOIN orders o ON oi.order_id = o.id
+		JOIN stations s ON oi.station_id = s.id
+		WHERE o.status IN ($1, $2)
+		  AND (o.held_at IS NULL OR o.released_at IS NOT NULL)
+		  AND oi.status <> $3
+		ORDER BY o.ordered_at ASC, o.id, s.name ASC, oi.station_id, oi.created_at ASC, oi.id
+	`
+
+	var items []models.KitchenItem
+	err := r.db.SelectContext(
+		ctx,
+		&items,
+		query,
+		models.OrderStatusNew,
+		models.OrderStatusInProgress,
+		models.OrderItemStatusCancelled,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kitchen items: %w", err)
+	}
+
+	return items, nil
+}
+
+// SetDisplayPriorities numbers the given items of a station's feed in order and clears the
+// manual position of its other items, in one transaction
+func (r *OrderRepository) SetDisplayPriorities(ctx context.Context, stationID uuid.UUID, itemIDs []uuid.UUID) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE order_items SET display_priority = NULL WHERE station_id = $1 AND display_priority IS NOT NULL",
+		stationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear display priorities: %w", err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE order_items oi SET display_priority = p.position
+		 FROM unnest($2::uuid[]) WITH ORDINALITY AS p(id, position)
+		 WHERE oi.id = p.id AND oi.station_id = $1`,
+		stationID,
+		pq.Array(itemIDs),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set display priorities: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrderHistory gets orders placed in [start, end), newest first
+func (r *OrderRepository) GetOrderHistory(ctx context.Context, start, end time.Time) ([]models.Order, error) {
+	query := `
+		SELECT id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
+		FROM orders
+		WHERE ordered_at >= $1 AND ordered_at < $2
+		ORDER BY ordered_at DESC
+		LIMIT 500
+	`
+
+	orders := []models.Order{}
+	err := r.db.SelectContext(ctx, &orders, query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+
+	return orders, nil
+}
+
+// EachOrder calls fn for every order placed in [start, end), oldest first, reading one row at
+// a time so a busy day is never held in memory. It stops at the first error fn returns.
+func (r *OrderRepository) EachOrder(ctx context.Context, start, end time.Time, fn func(models.Order) error) error {
+	query := `
+		SELECT id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at
+		FROM orders
+		WHERE ordered_at >= $1 AND ordered_at < $2
+		ORDER BY ordered_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryxContext(ctx, query, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to query orders: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var order models.Order
+		if err := rows.StructScan(&order); err != nil {
+			return fmt.Errorf("failed to scan order: %w", err)
+		}
+		if err := fn(order); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read orders: %w", err)
+	}
+
+	return nil
+}
+
+// VoidItem voids an order item, recording userID in the audit log
+func (r *OrderRepository) VoidItem(ctx context.Context, itemID, userID uuid.UUID, reason string) error {
+	// Start a transaction
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Cancel the item, unless a concurrent or retried void already has or it was completed,
+	// so its price comes off the total once
+	var orderInfo struct {
+		OrderID  uuid.UUID `db:"order_id"`
+		Price    float64   `db:"price"`
+		Quantity float64   `db:"quantity"`
 	}
 	err = tx.GetContext(
 		ctx,
 		&orderInfo,
-		"SELECT order_id, price, quantity FROM order_items WHERE id = $1",
+		`UPDATE order_items 
+		 SET status = $1, updated_at = $2, special_instructions = COALESCE(special_instructions, '') || E'\n[VOIDED: ' || $3 || ']'
+		 WHERE id = $4 AND status NOT IN ($1, $5)
+		 RETURNING order_id, price, quantity`,
+		models.OrderItemStatusCancelled,
+		time.Now(),
+		reason,
 		itemID,
+		models.OrderItemStatusCompleted,
 	)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = itemNotUpdated(ctx, tx, itemID)
+		return err
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get order info: %w", err)
+		return fmt.Errorf("failed to void order item: %w", err)
 	}
 
 	// Update order total
 	_, err = tx.ExecContext(
 		ctx,
 		"UPDATE orders SET total = total - $1, updated_at = $2 WHERE id = $3",
-		orderInfo.Price*float64(orderInfo.Quantity),
+		models.LineTotal(orderInfo.Price, orderInfo.Quantity),
 		time.Now(),
 		orderInfo.OrderID,
 	)
@@ -551,6 +1285,31 @@ func (r *OrderRepository) VoidItem(ctx context.Context, itemID uuid.UUID, reason
 		return fmt.Errorf("failed to update order total: %w", err)
 	}
 
+	newValues, err := json.Marshal(map[string]string{
+		"status": string(models.OrderItemStatusCancelled),
+		"reason": reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit values: %w", err)
+	}
+
+	err = insertAuditLog(ctx, tx, models.AuditLog{
+		UserID:    &userID,
+		Action:    "void",
+		TableName: "order_items",
+		RecordID:  itemID,
+		NewValues: newValues,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Voiding the last unfinished item finishes the order
+	err = r.completeIfItemsDone(ctx, tx, orderInfo.OrderID)
+	if err != nil {
+		return err
+	}
+
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -559,3 +1318,231 @@ func (r *OrderRepository) VoidItem(ctx context.Context, itemID uuid.UUID, reason
 
 	return nil
 }
+
+// GetUserStats aggregates the orders taken by a user between start (inclusive) and end (exclusive)
+func (r *OrderRepository) GetUserStats(ctx context.Context, userID uuid.UUID, start, end time.Time) (*models.UserStats, error) {
+	query := `
+		SELECT
+			COUNT(*) AS order_count,
+			COALESCE(SUM(o.total) FILTER (WHERE o.status <> $4), 0) AS total_sales,
+			COUNT(*) FILTER (WHERE o.status = $4) AS voided_orders,
+			COALESCE(SUM(v.item_count) FILTER (WHERE o.status <> $4), 0) AS voided_items
+		FROM orders o
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS item_count
+			FROM order_items oi
+			WHERE oi.order_id = o.id AND oi.status = $5
+		) v ON true
+		WHERE o.user_id = $1
+		  AND o.ordered_at >= $2
+		  AND o.ordered_at < $3
+	`
+
+	var stats models.UserStats
+	err := r.db.GetContext(ctx, &stats, query, userID, start, end, models.OrderStatusCancelled, models.OrderItemStatusCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// CancelStaleOrders cancels orders still in the new status that were placed before cutoff
+// and never had an item sent to a station, recording an audit entry for each
+func (r *OrderRepository) CancelStaleOrders(ctx context.Context, cutoff time.Time) ([]models.Order, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+
+	var cancelled []models.Order
+	err = tx.SelectContext(
+		ctx,
+		&cancelled,
+		`UPDATE orders o
+		 SET status = $1, updated_at = $2, updated_by = NULL
+		 WHERE o.status = $3
+		   AND o.ordered_at < $4
+		   AND (o.held_at IS NULL OR o.released_at IS NOT NULL)
+		   AND NOT EXISTS (
+		       SELECT 1 FROM order_items oi
+		       WHERE oi.order_id = o.id AND oi.sent_to_station_at IS NOT NULL
+		   )
+		 RETURNING id, user_id, order_number, status, total, notes, ordered_at, fired_at, completed_at, is_rush, held_at, released_at, customer_name, customer_phone, source, provider, external_id, created_by, updated_by, created_at, updated_at`,
+		models.OrderStatusCancelled,
+		now,
+		models.OrderStatusNew,
+		cutoff,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel stale orders: %w", err)
+	}
+
+	for _, order := range cancelled {
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE order_items SET status = $1, updated_at = $2 WHERE order_id = $3 AND status = $4",
+			models.OrderItemStatusCancelled,
+			now,
+			order.ID,
+			models.OrderItemStatusPending,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel stale order items: %w", err)
+		}
+
+		err = insertAuditLog(ctx, tx, models.AuditLog{
+			Action:    "auto_cancel",
+			TableName: "orders",
+			RecordID:  order.ID,
+			OldValues: json.RawMessage(`{"status":"new"}`),
+			NewValues: json.RawMessage(`{"status":"cancelled"}`),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return cancelled, nil
+}
+
+// GetTimeline builds an order's history from its own and its items' timestamps plus
+// audit log entries, oldest first
+func (r *OrderRepository) GetTimeline(ctx context.Context, orderID uuid.UUID) ([]models.OrderTimelineEvent, error) {
+	query := `
+		SELECT at, event, order_item_id, item_name, user_id, details FROM (
+			SELECT o.ordered_at AS at, 'order_created' AS event, NULL::uuid AS order_item_id, NULL AS item_name,
+			       COALESCE(o.created_by, o.user_id) AS user_id, NULL AS details, 0 AS seq
+			FROM orders o
+			WHERE o.id = $1
+
+			UNION ALL
+
+			SELECT oi.created_at, 'item_added', oi.id, mi.name, COALESCE(o.created_by, o.user_id), NULL, 1
+			FROM order_items oi
+			JOIN orders o ON oi.order_id = o.id
+			JOIN menu_items mi ON oi.menu_item_id = mi.id
+			WHERE oi.order_id = $1
+
+			UNION ALL
+
+			SELECT o.fired_at, 'order_fired', NULL, NULL, NULL, NULL, 2
+			FROM orders o
+			WHERE o.id = $1 AND o.fired_at IS NOT NULL
+
+			UNION ALL
+
+			SELECT oi.sent_to_station_at, 'item_sent_to_station', oi.id, mi.name, NULL, s.name, 3
+			FROM order_items oi
+			JOIN menu_items mi ON oi.menu_item_id = mi.id
+			JOIN stations s ON oi.station_id = s.id
+			WHERE oi.order_id = $1 AND oi.sent_to_station_at IS NOT NULL
+
+			UNION ALL
+
+			SELECT oi.completed_at, 'item_completed', oi.id, mi.name, NULL, NULL, 4
+			FROM order_items oi
+			JOIN menu_items mi ON oi.menu_item_id = mi.id
+			WHERE oi.order_id = $1 AND oi.completed_at IS NOT NULL
+
+			UNION ALL
+
+			SELECT al.created_at, 'item_' || al.action, oi.id, mi.name, al.user_id, al.new_values->>'reason', 5
+			FROM audit_logs al
+			JOIN order_items oi ON al.table_name = 'order_items' AND al.record_id = oi.id
+			JOIN menu_items mi ON oi.menu_item_id = mi.id
+			WHERE oi.order_id = $1
+
+			UNION ALL
+
+			SELECT al.created_at, 'order_' || al.action, NULL, NULL, al.user_id, al.new_values->>'status', 6
+			FROM audit_logs al
+			WHERE al.table_name = 'orders' AND al.record_id = $1
+
+			UNION ALL
+
+			SELECT o.completed_at, 'order_completed', NULL, NULL, NULL, NULL, 7
+			FROM orders o
+			WHERE o.id = $1 AND o.completed_at IS NOT NULL
+		) events
+		ORDER BY at ASC, seq ASC
+	`
+
+	var events []models.OrderTimelineEvent
+	err := r.db.SelectContext(ctx, &events, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order timeline: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetVoidedItems gets the items voided in [start, end), oldest void first. The void time
+// comes from the audit log, falling back to the item's last update.
+func (r *OrderRepository) GetVoidedItems(ctx context.Context, start, end time.Time) ([]models.VoidedItem, error) {
+	query := `
+		SELECT oi.id AS item_id, oi.order_id, o.order_number, oi.menu_item_id, mi.name, oi.station_id,
+		       oi.quantity, oi.price, v.reason, oi.sent_to_station_at, COALESCE(v.voided_at, oi.updated_at) AS voided_at
+		FROM order_items oi
+		JOIN orders o ON oi.order_id = o.id
+		JOIN menu_items mi ON oi.menu_item_id = mi.id
+		LEFT JOIN LATERAL (
+			SELECT al.created_at AS voided_at, al.new_values->>'reason' AS reason
+			FROM audit_logs al
+			WHERE al.table_name = 'order_items' AND al.action = 'void' AND al.record_id = oi.id
+			ORDER BY al.created_at DESC
+			LIMIT 1
+		) v ON TRUE
+		WHERE oi.status = $1
+		  AND COALESCE(v.voided_at, oi.updated_at) >= $2
+		  AND COALESCE(v.voided_at, oi.updated_at) < $3
+		ORDER BY voided_at ASC, oi.id
+	`
+
+	var items []models.VoidedItem
+	err := r.db.SelectContext(ctx, &items, query, models.OrderItemStatusCancelled, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get voided items: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetPrepTimeStats computes prep-time percentiles for items sent to a station in [start, end).
+// The first row is the overall figures, followed by one row per station. Voided items are excluded.
+func (r *OrderRepository) GetPrepTimeStats(ctx context.Context, start, end time.Time) ([]models.PrepTimeStats, error) {
+	query := `
+		SELECT oi.station_id, s.name AS station_name, COUNT(*) AS item_count,
+		       COALESCE(PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM oi.completed_at - oi.sent_to_station_at)::float8), 0) AS p50,
+		       COALESCE(PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM oi.completed_at - oi.sent_to_station_at)::float8), 0) AS p90,
+		       COALESCE(PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM oi.completed_at - oi.sent_to_station_at)::float8), 0) AS p99
+		FROM order_items oi
+		JOIN stations s ON oi.station_id = s.id
+		WHERE oi.status = $1
+		  AND oi.sent_to_station_at IS NOT NULL
+		  AND oi.completed_at IS NOT NULL
+		  AND oi.sent_to_station_at >= $2
+		  AND oi.sent_to_station_at < $3
+		GROUP BY GROUPING SETS ((), (oi.station_id, s.name))
+		ORDER BY oi.station_id IS NOT NULL, s.name ASC
+	`
+
+	var stats []models.PrepTimeStats
+	err := r.db.SelectContext(ctx, &stats, query, models.OrderItemStatusCompleted, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prep time stats: %w", err)
+	}
+
+	return stats, nil
+}
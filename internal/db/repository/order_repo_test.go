@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+// A feed merged from several stations is one list, so the order can't depend on which
+// station an item is at and must end on a unique key
+func TestStationFeedOrder(t *testing.T) {
+	for _, groupBy := range []models.StationItemsGroupBy{models.StationItemsGroupByTime, models.StationItemsGroupByOrder} {
+		t.Run(string(groupBy), func(t *testing.T) {
+			orderBy := stationFeedOrder(groupBy)
+
+			if !strings.HasPrefix(orderBy, "oi.display_priority ASC NULLS LAST, (o.is_rush OR oi.is_rush") {
+				t.Errorf("placed and rush items aren't first: %s", orderBy)
+			}
+			if !strings.HasSuffix(orderBy, ", oi.id") {
+				t.Errorf("ties aren't broken by item ID: %s", orderBy)
+			}
+			if strings.Contains(orderBy, "station_id") {
+				t.Errorf("items are grouped by station: %s", orderBy)
+			}
+		})
+	}
+
+	if got := stationFeedOrder(models.StationItemsGroupByOrder); !strings.Contains(got, "o.ordered_at ASC, o.id, oi.seat") {
+		t.Errorf("order grouping doesn't keep orders and seats together: %s", got)
+	}
+	if got := stationFeedOrder(models.StationItemsGroupByTime); !strings.Contains(got, "oi.sent_to_station_at ASC") {
+		t.Errorf("time grouping isn't by time sent: %s", got)
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
@@ -11,6 +12,12 @@ import (
 	"github.com/pizza-nz/restaurant-service/internal/models"
 )
 
+// ErrDefaultPrinterConflict is returned when a concurrent change already claimed the default printer
+var ErrDefaultPrinterConflict = errors.New("another printer was made default concurrently")
+
+// defaultPrinterLockKey serializes transactions that change the default printer for a purpose
+const defaultPrinterLockKey = "printers.default."
+
 // PrinterRepository handles printer and display data access
 type PrinterRepository struct {
 	db *sqlx.DB
@@ -24,7 +31,7 @@ func NewPrinterRepository(db *sqlx.DB) *PrinterRepository {
 // GetPrinterByID retrieves a printer by ID
 func (r *PrinterRepository) GetPrinterByID(ctx context.Context, id uuid.UUID) (*models.Printer, error) {
 	query := `
-		SELECT id, name, type, ip_address, port, model, is_default, is_active, created_at, updated_at
+		SELECT id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
 		FROM printers
 		WHERE id = $1
 	`
@@ -32,7 +39,7 @@ func (r *PrinterRepository) GetPrinterByID(ctx context.Context, id uuid.UUID) (*
 	var printer models.Printer
 	err := r.db.GetContext(ctx, &printer, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get printer: %w", err)
+		return nil, fmt.Errorf("failed to get printer: %w", notFound(err))
 	}
 
 	return &printer, nil
@@ -41,7 +48,7 @@ func (r *PrinterRepository) GetPrinterByID(ctx context.Context, id uuid.UUID) (*
 // ListPrinters retrieves all printers
 func (r *PrinterRepository) ListPrinters(ctx context.Context) ([]models.Printer, error) {
 	query := `
-		SELECT id, name, type, ip_address, port, model, is_default, is_active, created_at, updated_at
+		SELECT id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
 		FROM printers
 		ORDER BY name ASC
 	`
@@ -55,19 +62,18 @@ func (r *PrinterRepository) ListPrinters(ctx context.Context) ([]models.Printer,
 	return printers, nil
 }
 
-// GetDefaultPrinter retrieves the default printer
-func (r *PrinterRepository) GetDefaultPrinter(ctx context.Context) (*models.Printer, error) {
+// GetDefaultPrinter retrieves the default printer for a purpose
+func (r *PrinterRepository) GetDefaultPrinter(ctx context.Context, purpose models.PrinterPurpose) (*models.Printer, error) {
 	query := `
-		SELECT id, name, type, ip_address, port, model, is_default, is_active, created_at, updated_at
+		SELECT id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
 		FROM printers
-		WHERE is_default = true AND is_active = true
-		LIMIT 1
+		WHERE default_for = $1 AND is_active = true
 	`
 
 	var printer models.Printer
-	err := r.db.GetContext(ctx, &printer, query)
+	err := r.db.GetContext(ctx, &printer, query, purpose)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get default printer: %w", err)
+		return nil, fmt.Errorf("failed to get default printer: %w", notFound(err))
 	}
 
 	return &printer, nil
@@ -86,22 +92,20 @@ func (r *PrinterRepository) CreatePrinter(ctx context.Context, printer models.Pr
 		}
 	}()
 
-	// If this printer is set as default, unset any existing default
-	if printer.IsDefault {
-		_, err = tx.ExecContext(
-			ctx,
-			"UPDATE printers SET is_default = false WHERE is_default = true",
-		)
+	// If this printer is set as default, unset any existing default for the same purpose
+	purpose := defaultPurpose(printer)
+	if purpose != nil {
+		err = r.clearDefault(ctx, tx, uuid.Nil, *purpose)
 		if err != nil {
-			return nil, fmt.Errorf("failed to unset default printers: %w", err)
+			return nil, err
 		}
 	}
 
 	// Insert the printer
 	query := `
-		INSERT INTO printers (name, type, ip_address, port, model, is_default, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, name, type, ip_address, port, model, is_default, is_active, created_at, updated_at
+		INSERT INTO printers (name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		RETURNING id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
 	`
 
 	var createdPrinter models.Printer
@@ -114,9 +118,16 @@ func (r *PrinterRepository) CreatePrinter(ctx context.Context, printer models.Pr
 		printer.IPAddress,
 		printer.Port,
 		printer.Model,
-		printer.IsDefault,
+		charsPerLineOrDefault(printer.CharsPerLine),
+		isReceiptDefault(purpose),
+		purpose,
 		printer.IsActive,
+		printer.CreatedBy,
 	)
+	if isUniqueViolation(err) {
+		err = ErrDefaultPrinterConflict
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create printer: %w", err)
 	}
@@ -143,24 +154,22 @@ func (r *PrinterRepository) UpdatePrinter(ctx context.Context, printer models.Pr
 		}
 	}()
 
-	// If this printer is set as default, unset any existing default
-	if printer.IsDefault {
-		_, err = tx.ExecContext(
-			ctx,
-			"UPDATE printers SET is_default = false WHERE is_default = true AND id != $1",
-			printer.ID,
-		)
+	// If this printer is set as default, unset any existing default for the same purpose
+	purpose := defaultPurpose(printer)
+	if purpose != nil {
+		err = r.clearDefault(ctx, tx, printer.ID, *purpose)
 		if err != nil {
-			return nil, fmt.Errorf("failed to unset default printers: %w", err)
+			return nil, err
 		}
 	}
 
 	// Update the printer
 	query := `
 		UPDATE printers
-		SET name = $1, type = $2, ip_address = $3, port = $4, model = $5, is_default = $6, is_active = $7, updated_at = $8
-		WHERE id = $9
-		RETURNING id, name, type, ip_address, port, model, is_default, is_active, created_at, updated_at
+		SET name = $1, type = $2, ip_address = $3, port = $4, model = $5, chars_per_line = $6, is_default = $7, default_for = $8,
+		    is_active = $9, updated_at = $10, updated_by = $11
+		WHERE id = $12
+		RETURNING id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
 	`
 
 	var updatedPrinter models.Printer
@@ -173,11 +182,18 @@ func (r *PrinterRepository) UpdatePrinter(ctx context.Context, printer models.Pr
 		printer.IPAddress,
 		printer.Port,
 		printer.Model,
-		printer.IsDefault,
+		charsPerLineOrDefault(printer.CharsPerLine),
+		isReceiptDefault(purpose),
+		purpose,
 		printer.IsActive,
 		time.Now(),
+		printer.UpdatedBy,
 		printer.ID,
 	)
+	if isUniqueViolation(err) {
+		err = ErrDefaultPrinterConflict
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to update printer: %w", err)
 	}
@@ -191,6 +207,100 @@ func (r *PrinterRepository) UpdatePrinter(ctx context.Context, printer models.Pr
 	return &updatedPrinter, nil
 }
 
+// charsPerLineOrDefault falls back to the default width when none is set
+func charsPerLineOrDefault(chars int) int {
+	if chars == 0 {
+		return models.DefaultPrinterCharsPerLine
+	}
+	return chars
+}
+
+// defaultPurpose is what a printer is the default for, is_default alone means receipts
+func defaultPurpose(printer models.Printer) *models.PrinterPurpose {
+	if printer.DefaultFor != nil {
+		return printer.DefaultFor
+	}
+	if printer.IsDefault {
+		purpose := models.PrinterPurposeReceipt
+		return &purpose
+	}
+	return nil
+}
+
+// isReceiptDefault reports whether is_default should be set for a default purpose
+func isReceiptDefault(purpose *models.PrinterPurpose) bool {
+	return purpose != nil && *purpose == models.PrinterPurposeReceipt
+}
+
+// SetDefaultPrinter atomically makes an active printer the only default for a purpose, recording userID
+// as the editor. A printer is the default for one purpose at most, so this replaces any purpose it had.
+func (r *PrinterRepository) SetDefaultPrinter(ctx context.Context, id, userID uuid.UUID, purpose models.PrinterPurpose) (*models.Printer, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = r.clearDefault(ctx, tx, id, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE printers
+		SET is_default = $1, default_for = $2, updated_at = $3, updated_by = $4
+		WHERE id = $5 AND is_active = true
+		RETURNING id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
+	`
+
+	var printer models.Printer
+	err = tx.GetContext(ctx, &printer, query, isReceiptDefault(&purpose), purpose, time.Now(), userID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("printer %w or inactive", ErrNotFound)
+		return nil, err
+	}
+	if isUniqueViolation(err) {
+		err = ErrDefaultPrinterConflict
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set default printer: %w", err)
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &printer, nil
+}
+
+// clearDefault takes the default printer lock for purpose and unsets that default on every printer except keepID
+// The lock is held until tx ends, so concurrent default changes are applied one at a time
+func (r *PrinterRepository) clearDefault(ctx context.Context, tx *sqlx.Tx, keepID uuid.UUID, purpose models.PrinterPurpose) error {
+	_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", defaultPrinterLockKey+string(purpose))
+	if err != nil {
+		return fmt.Errorf("failed to lock default printer: %w", err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE printers SET is_default = false, default_for = NULL WHERE default_for = $1 AND id != $2",
+		purpose,
+		keepID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to unset default printers: %w", err)
+	}
+
+	return nil
+}
+
 // DeletePrinter deletes a printer
 func (r *PrinterRepository) DeletePrinter(ctx context.Context, id uuid.UUID) error {
 	// Check if there are any stations using this printer
@@ -198,7 +308,7 @@ func (r *PrinterRepository) DeletePrinter(ctx context.Context, id uuid.UUID) err
 	err := r.db.GetContext(
 		ctx,
 		&count,
-		"SELECT COUNT(*) FROM stations WHERE printer_id = $1",
+		"SELECT COUNT(DISTINCT station_id) FROM (SELECT id AS station_id FROM stations WHERE printer_id = $1 UNION SELECT station_id FROM station_printers WHERE printer_id = $1) used",
 		id,
 	)
 	if err != nil {
@@ -223,7 +333,7 @@ func (r *PrinterRepository) DeletePrinter(ctx context.Context, id uuid.UUID) err
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("printer not found")
+		return fmt.Errorf("printer %w", ErrNotFound)
 	}
 
 	return nil
@@ -232,7 +342,7 @@ func (r *PrinterRepository) DeletePrinter(ctx context.Context, id uuid.UUID) err
 // GetDisplayByID retrieves a display by ID
 func (r *PrinterRepository) GetDisplayByID(ctx context.Context, id uuid.UUID) (*models.Display, error) {
 	query := `
-		SELECT id, name, type, ip_address, is_active, created_at, updated_at
+		SELECT id, name, type, ip_address, is_active, last_seen_at, created_at, updated_at
 		FROM displays
 		WHERE id = $1
 	`
@@ -240,16 +350,18 @@ func (r *PrinterRepository) GetDisplayByID(ctx context.Context, id uuid.UUID) (*
 	var display models.Display
 	err := r.db.GetContext(ctx, &display, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get display: %w", err)
+		return nil, fmt.Errorf("failed to get display: %w", notFound(err))
 	}
 
+	display.Online = display.IsOnline(time.Now())
+
 	return &display, nil
 }
 
 // ListDisplays retrieves all displays
 func (r *PrinterRepository) ListDisplays(ctx context.Context) ([]models.Display, error) {
 	query := `
-		SELECT id, name, type, ip_address, is_active, created_at, updated_at
+		SELECT id, name, type, ip_address, is_active, last_seen_at, created_at, updated_at
 		FROM displays
 		ORDER BY name ASC
 	`
@@ -260,15 +372,39 @@ func (r *PrinterRepository) ListDisplays(ctx context.Context) ([]models.Display,
 		return nil, fmt.Errorf("failed to list displays: %w", err)
 	}
 
+	now := time.Now()
+	for i := range displays {
+		displays[i].Online = displays[i].IsOnline(now)
+	}
+
 	return displays, nil
 }
 
+// TouchDisplay records a heartbeat from a display
+func (r *PrinterRepository) TouchDisplay(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE displays SET last_seen_at = $1 WHERE id = $2", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to record display heartbeat: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("display %w", ErrNotFound)
+	}
+
+	return nil
+}
+
 // CreateDisplay creates a new display
 func (r *PrinterRepository) CreateDisplay(ctx context.Context, display models.Display) (*models.Display, error) {
 	query := `
 		INSERT INTO displays (name, type, ip_address, is_active)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id, name, type, ip_address, is_active, created_at, updated_at
+		RETURNING id, name, type, ip_address, is_active, last_seen_at, created_at, updated_at
 	`
 
 	var createdDisplay models.Display
@@ -294,7 +430,7 @@ func (r *PrinterRepository) UpdateDisplay(ctx context.Context, display models.Di
 		UPDATE displays
 		SET name = $1, type = $2, ip_address = $3, is_active = $4, updated_at = $5
 		WHERE id = $6
-		RETURNING id, name, type, ip_address, is_active, created_at, updated_at
+		RETURNING id, name, type, ip_address, is_active, last_seen_at, created_at, updated_at
 	`
 
 	var updatedDisplay models.Display
@@ -347,7 +483,7 @@ func (r *PrinterRepository) DeleteDisplay(ctx context.Context, id uuid.UUID) err
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("display not found")
+		return fmt.Errorf("display %w", ErrNotFound)
 	}
 
 	return nil
@@ -11,6 +11,7 @@ type Repositories struct {
 	Order   *OrderRepository
 	Station *StationRepository
 	Printer *PrinterRepository
+	Audit   *AuditRepository
 }
 
 // NewRepositories creates a new repositories container
@@ -21,5 +22,6 @@ func NewRepositories(database *db.Postgres) *Repositories {
 		Order:   NewOrderRepository(database.DB),
 		Station: NewStationRepository(database.DB),
 		Printer: NewPrinterRepository(database.DB),
+		Audit:   NewAuditRepository(database.DB),
 	}
 }
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
@@ -24,7 +23,7 @@ func NewStationRepository(db *sqlx.DB) *StationRepository {
 // GetByID retrieves a station by ID
 func (r *StationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Station, error) {
 	query := `
-		SELECT id, name, type, printer_id, display_id, is_active, created_at, updated_at
+		SELECT id, name, type, printer_id, display_id, is_active, created_by, updated_by, created_at, updated_at
 		FROM stations
 		WHERE id = $1
 	`
@@ -32,7 +31,7 @@ func (r *StationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 	var station models.Station
 	err := r.db.GetContext(ctx, &station, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get station: %w", err)
+		return nil, fmt.Errorf("failed to get station: %w", notFound(err))
 	}
 
 	// Get printer if associated
@@ -53,13 +52,177 @@ func (r *StationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 		station.Display = display
 	}
 
+	printers, err := r.GetStationPrinters(ctx, station.ID)
+	if err != nil {
+		return nil, err
+	}
+	station.Printers = printers
+
 	return &station, nil
 }
 
+// GetStationPrinters retrieves a station's printer assignments with their printers,
+// or ErrNotFound if the station doesn't exist
+func (r *StationRepository) GetStationPrinters(ctx context.Context, stationID uuid.UUID) ([]models.StationPrinter, error) {
+	query := `
+		SELECT sp.station_id, sp.printer_id, sp.role, sp.created_at,
+			p.id AS "printer.id", p.name AS "printer.name", p.type AS "printer.type",
+			p.ip_address AS "printer.ip_address", p.port AS "printer.port", p.model AS "printer.model",
+			p.chars_per_line AS "printer.chars_per_line", p.is_default AS "printer.is_default",
+			p.default_for AS "printer.default_for", p.is_active AS "printer.is_active",
+			p.created_by AS "printer.created_by", p.updated_by AS "printer.updated_by",
+			p.created_at AS "printer.created_at", p.updated_at AS "printer.updated_at"
+		FROM station_printers sp
+		JOIN printers p ON p.id = sp.printer_id
+		WHERE sp.station_id = $1
+		ORDER BY sp.role ASC
+	`
+
+	var rows []struct {
+		models.StationPrinter
+		Printer models.Printer `db:"printer"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, stationID); err != nil {
+		return nil, fmt.Errorf("failed to get station printers: %w", err)
+	}
+
+	// No assignments could also mean no station
+	if len(rows) == 0 {
+		var id uuid.UUID
+		if err := r.db.GetContext(ctx, &id, "SELECT id FROM stations WHERE id = $1", stationID); err != nil {
+			return nil, fmt.Errorf("failed to get station: %w", notFound(err))
+		}
+	}
+
+	assignments := make([]models.StationPrinter, 0, len(rows))
+	for _, row := range rows {
+		assignment := row.StationPrinter
+		printer := row.Printer
+		assignment.Printer = &printer
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, nil
+}
+
+// GetPrinterForRole retrieves the printer a station uses for role. A station with no
+// ticket assignment falls back to its printer_id so single-printer setups keep working.
+func (r *StationRepository) GetPrinterForRole(ctx context.Context, stationID uuid.UUID, role models.PrinterRole) (*models.Printer, error) {
+	query := `
+		SELECT printer_id FROM station_printers
+		WHERE station_id = $1 AND role = $2
+	`
+	if role == models.PrinterRoleTicket {
+		query = `
+			SELECT COALESCE(
+				(SELECT printer_id FROM station_printers WHERE station_id = s.id AND role = $2),
+				s.printer_id
+			)
+			FROM stations s
+			WHERE s.id = $1
+		`
+	}
+
+	var printerID *uuid.UUID
+	if err := r.db.GetContext(ctx, &printerID, query, stationID, role); err != nil {
+		return nil, fmt.Errorf("failed to get station printer: %w", notFound(err))
+	}
+	if printerID == nil {
+		return nil, fmt.Errorf("station printer %w", ErrNotFound)
+	}
+
+	return r.getPrinter(ctx, *printerID)
+}
+
+// SetStationPrinters replaces every printer assignment of a station in one transaction
+// and mirrors the ticket printer into stations.printer_id for older clients
+func (r *StationRepository) SetStationPrinters(ctx context.Context, stationID, userID uuid.UUID, assignments []models.StationPrinterAssignment) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var ticketPrinterID *uuid.UUID
+	for _, a := range assignments {
+		if a.Role == models.PrinterRoleTicket {
+			ticketPrinterID = &a.PrinterID
+		}
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE stations SET printer_id = $1, updated_at = NOW(), updated_by = $2 WHERE id = $3",
+		ticketPrinterID,
+		userID,
+		stationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update station: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("station %w", ErrNotFound)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM station_printers WHERE station_id = $1", stationID); err != nil {
+		return fmt.Errorf("failed to clear station printers: %w", err)
+	}
+
+	for _, a := range assignments {
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO station_printers (station_id, printer_id, role) VALUES ($1, $2, $3)",
+			stationID,
+			a.PrinterID,
+			a.Role,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to assign station printer: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// syncTicketPrinter keeps the ticket assignment in line with a station's printer_id
+// after the single-printer field is written directly
+func (r *StationRepository) syncTicketPrinter(ctx context.Context, tx *sqlx.Tx, station *models.Station) error {
+	var err error
+	if station.PrinterID == nil {
+		_, err = tx.ExecContext(ctx, "DELETE FROM station_printers WHERE station_id = $1 AND role = $2", station.ID, models.PrinterRoleTicket)
+	} else {
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO station_printers (station_id, printer_id, role) VALUES ($1, $2, $3)
+			 ON CONFLICT (station_id, role) DO UPDATE SET printer_id = EXCLUDED.printer_id`,
+			station.ID,
+			*station.PrinterID,
+			models.PrinterRoleTicket,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sync station ticket printer: %w", err)
+	}
+
+	return nil
+}
+
 // getPrinter retrieves a printer by ID (helper method)
 func (r *StationRepository) getPrinter(ctx context.Context, id uuid.UUID) (*models.Printer, error) {
 	query := `
-		SELECT id, name, type, ip_address, port, model, is_default, is_active, created_at, updated_at
+		SELECT id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
 		FROM printers
 		WHERE id = $1
 	`
@@ -93,7 +256,7 @@ func (r *StationRepository) getDisplay(ctx context.Context, id uuid.UUID) (*mode
 // List retrieves all stations
 func (r *StationRepository) List(ctx context.Context) ([]models.Station, error) {
 	query := `
-		SELECT id, name, type, printer_id, display_id, is_active, created_at, updated_at
+		SELECT id, name, type, printer_id, display_id, is_active, created_by, updated_by, created_at, updated_at
 		FROM stations
 		ORDER BY name ASC
 	`
@@ -121,7 +284,7 @@ func (r *StationRepository) List(ctx context.Context) ([]models.Station, error)
 	printers := make(map[uuid.UUID]*models.Printer)
 	if len(printerIDs) > 0 {
 		query := `
-			SELECT id, name, type, ip_address, port, model, is_default, is_active, created_at, updated_at
+			SELECT id, name, type, ip_address, port, model, chars_per_line, is_default, default_for, is_active, created_by, updated_by, created_at, updated_at
 			FROM printers
 			WHERE id IN (?)
 		`
@@ -186,15 +349,25 @@ func (r *StationRepository) List(ctx context.Context) ([]models.Station, error)
 }
 
 // Create creates a new station
-func (r *StationRepository) Create(ctx context.Context, station models.Station) (*models.Station, error) {
+func (r *StationRepository) Create(ctx context.Context, station models.Station) (_ *models.Station, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
 	query := `
-		INSERT INTO stations (name, type, printer_id, display_id, is_active)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, name, type, printer_id, display_id, is_active, created_at, updated_at
+		INSERT INTO stations (name, type, printer_id, display_id, is_active, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id, name, type, printer_id, display_id, is_active, created_by, updated_by, created_at, updated_at
 	`
 
 	var createdStation models.Station
-	err := r.db.GetContext(
+	err = tx.GetContext(
 		ctx,
 		&createdStation,
 		query,
@@ -203,11 +376,20 @@ func (r *StationRepository) Create(ctx context.Context, station models.Station)
 		station.PrinterID,
 		station.DisplayID,
 		station.IsActive,
+		station.CreatedBy,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create station: %w", err)
 	}
 
+	if err = r.syncTicketPrinter(ctx, tx, &createdStation); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	// Get printer if associated
 	if createdStation.PrinterID != nil {
 		printer, err := r.getPrinter(ctx, *createdStation.PrinterID)
@@ -230,16 +412,26 @@ func (r *StationRepository) Create(ctx context.Context, station models.Station)
 }
 
 // Update updates a station
-func (r *StationRepository) Update(ctx context.Context, station models.Station) (*models.Station, error) {
+func (r *StationRepository) Update(ctx context.Context, station models.Station) (_ *models.Station, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
 	query := `
 		UPDATE stations
-		SET name = $1, type = $2, printer_id = $3, display_id = $4, is_active = $5, updated_at = $6
-		WHERE id = $7
-		RETURNING id, name, type, printer_id, display_id, is_active, created_at, updated_at
+		SET name = $1, type = $2, printer_id = $3, display_id = $4, is_active = $5, updated_at = $6, updated_by = $7
+		WHERE id = $8
+		RETURNING id, name, type, printer_id, display_id, is_active, created_by, updated_by, created_at, updated_at
 	`
 
 	var updatedStation models.Station
-	err := r.db.GetContext(
+	err = tx.GetContext(
 		ctx,
 		&updatedStation,
 		query,
@@ -249,10 +441,19 @@ func (r *StationRepository) Update(ctx context.Context, station models.Station)
 		station.DisplayID,
 		station.IsActive,
 		time.Now(),
+		station.UpdatedBy,
 		station.ID,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update station: %w", err)
+		return nil, fmt.Errorf("failed to update station: %w", notFound(err))
+	}
+
+	if err = r.syncTicketPrinter(ctx, tx, &updatedStation); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	// Get printer if associated
@@ -276,6 +477,92 @@ func (r *StationRepository) Update(ctx context.Context, station models.Station)
 	return &updatedStation, nil
 }
 
+// GetRoutedMenuItems lists the menu items whose routing rules point at a station, by name
+func (r *StationRepository) GetRoutedMenuItems(ctx context.Context, stationID uuid.UUID) ([]models.StationMenuItem, error) {
+	query := `
+		SELECT rr.id AS rule_id, mi.id AS menu_item_id, mi.name, mi.category_id, rr.priority, mi.available, mi.deleted_at
+		FROM routing_rules rr
+		JOIN menu_items mi ON mi.id = rr.menu_item_id
+		WHERE rr.station_id = $1
+		ORDER BY mi.name ASC, mi.id ASC
+	`
+
+	items := []models.StationMenuItem{}
+	if err := r.db.SelectContext(ctx, &items, query, stationID); err != nil {
+		return nil, fmt.Errorf("failed to get routed menu items: %w", err)
+	}
+
+	return items, nil
+}
+
+// ReassignRouting repoints every routing rule of a station to another station in one transaction.
+// Items already routed to the target keep the target's rule and lose the old one. With
+// moveOpenItems, pending and in-progress order items follow too.
+func (r *StationRepository) ReassignRouting(ctx context.Context, stationID, targetID uuid.UUID, moveOpenItems bool) (result *models.RoutingReassignResult, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	result = &models.RoutingReassignResult{StationID: stationID, TargetStationID: targetID}
+
+	moved, err := tx.ExecContext(
+		ctx,
+		`UPDATE routing_rules SET station_id = $2, updated_at = NOW()
+		 WHERE station_id = $1
+		 AND menu_item_id NOT IN (SELECT menu_item_id FROM routing_rules WHERE station_id = $2)`,
+		stationID,
+		targetID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reassign routing rules: %w", err)
+	}
+	rows, err := moved.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reassigned routing rules: %w", err)
+	}
+	result.Moved = int(rows)
+
+	merged, err := tx.ExecContext(ctx, "DELETE FROM routing_rules WHERE station_id = $1", stationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove merged routing rules: %w", err)
+	}
+	if rows, err = merged.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to count merged routing rules: %w", err)
+	}
+	result.Merged = int(rows)
+
+	if moveOpenItems {
+		items, err := tx.ExecContext(
+			ctx,
+			`UPDATE order_items SET station_id = $2, display_priority = NULL, updated_at = NOW()
+			 WHERE station_id = $1 AND status IN ($3, $4)`,
+			stationID,
+			targetID,
+			models.OrderItemStatusPending,
+			models.OrderItemStatusInProgress,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move open order items: %w", err)
+		}
+		if rows, err = items.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to count moved order items: %w", err)
+		}
+		result.ItemsMoved = int(rows)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
 // Delete deletes a station
 func (r *StationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	// Check if there are any routing rules using this station
@@ -311,8 +598,64 @@ func (r *StationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("station not found")
+		return fmt.Errorf("station %w", ErrNotFound)
 	}
 
 	return nil
 }
+
+// AssignCategoryRouting routes every live item in a category to a station in one transaction.
+// Items that already have a rule for the station are skipped, or have their priority
+// overwritten when replace is set. It returns how many items were assigned and skipped.
+func (r *StationRepository) AssignCategoryRouting(ctx context.Context, categoryID, stationID uuid.UUID, priority int, replace bool) (assigned, skipped int, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var total int
+	err = tx.GetContext(
+		ctx,
+		&total,
+		"SELECT COUNT(*) FROM menu_items WHERE category_id = $1 AND deleted_at IS NULL",
+		categoryID,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count category items: %w", err)
+	}
+
+	conflict := "DO NOTHING"
+	if replace {
+		conflict = "DO UPDATE SET priority = EXCLUDED.priority, updated_at = NOW()"
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO routing_rules (menu_item_id, station_id, priority)
+		 SELECT id, $2, $3 FROM menu_items
+		 WHERE category_id = $1 AND deleted_at IS NULL
+		 ON CONFLICT (menu_item_id, station_id) `+conflict,
+		categoryID,
+		stationID,
+		priority,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to assign routing rules: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count assigned routing rules: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(rows), total - int(rows), nil
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/pizza-nz/restaurant-service/internal/models"
 )
 
+// ErrDuplicateUsername is returned when creating a user whose username is taken
+var ErrDuplicateUsername = errors.New("username already exists")
+
 // UserRepository handles user data access
 type UserRepository struct {
 	db *sqlx.DB
@@ -24,7 +27,7 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, username, password_hash, name, role, is_active, created_at, updated_at
+		SELECT id, username, password_hash, name, role, is_active, must_change_password, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -32,7 +35,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	var user models.User
 	err := r.db.GetContext(ctx, &user, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", notFound(err))
 	}
 
 	return &user, nil
@@ -41,7 +44,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 // GetByUsername retrieves a user by username
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
-		SELECT id, username, password_hash, name, role, is_active, created_at, updated_at
+		SELECT id, username, password_hash, name, role, is_active, must_change_password, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -49,7 +52,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	var user models.User
 	err := r.db.GetContext(ctx, &user, query, username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by username: %w", err)
+		return nil, fmt.Errorf("failed to get user by username: %w", notFound(err))
 	}
 
 	return &user, nil
@@ -58,7 +61,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 // List retrieves all users
 func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
 	query := `
-		SELECT id, username, password_hash, name, role, is_active, created_at, updated_at
+		SELECT id, username, password_hash, name, role, is_active, must_change_password, created_at, updated_at
 		FROM users
 		ORDER BY username ASC
 	`
@@ -75,9 +78,9 @@ func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, user models.User) (*models.User, error) {
 	query := `
-		INSERT INTO users (username, password_hash, name, role, is_active)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, username, password_hash, name, role, is_active, created_at, updated_at
+		INSERT INTO users (username, password_hash, name, role, is_active, must_change_password)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, username, password_hash, name, role, is_active, must_change_password, created_at, updated_at
 	`
 
 	var createdUser models.User
@@ -90,7 +93,11 @@ func (r *UserRepository) Create(ctx context.Context, user models.User) (*models.
 		user.Name,
 		user.Role,
 		user.IsActive,
+		user.MustChangePassword,
 	)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateUsername, user.Username)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -104,7 +111,7 @@ func (r *UserRepository) Update(ctx context.Context, user models.User) (*models.
 		UPDATE users
 		SET username = $1, name = $2, role = $3, is_active = $4, updated_at = $5
 		WHERE id = $6
-		RETURNING id, username, password_hash, name, role, is_active, created_at, updated_at
+		RETURNING id, username, password_hash, name, role, is_active, must_change_password, created_at, updated_at
 	`
 
 	var updatedUser models.User
@@ -126,11 +133,11 @@ func (r *UserRepository) Update(ctx context.Context, user models.User) (*models.
 	return &updatedUser, nil
 }
 
-// UpdatePassword updates a user's password
+// UpdatePassword updates a user's password, clearing any forced password change
 func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
 	query := `
 		UPDATE users
-		SET password_hash = $1, updated_at = $2
+		SET password_hash = $1, must_change_password = FALSE, updated_at = $2
 		WHERE id = $3
 	`
 
@@ -145,7 +152,7 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passw
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("user not found")
+		return fmt.Errorf("user %w", ErrNotFound)
 	}
 
 	return nil
@@ -169,7 +176,7 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("user not found")
+		return fmt.Errorf("user %w", ErrNotFound)
 	}
 
 	return nil
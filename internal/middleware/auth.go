@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/pizza-nz/restaurant-service/internal/api"
 	"github.com/pizza-nz/restaurant-service/internal/models"
 	"github.com/pizza-nz/restaurant-service/internal/service"
 )
@@ -20,6 +21,9 @@ const (
 	UserKey     contextKey = "user"
 )
 
+// changePasswordPath is the only route open to a token whose user must change their password
+const changePasswordPath = "/auth/password"
+
 // Auth middleware for authenticating requests
 func Auth(authService *service.AuthService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -27,14 +31,14 @@ func Auth(authService *service.AuthService) func(http.Handler) http.Handler {
 			// Get the Authorization header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+				api.Unauthorized(w, "Authorization header required")
 				return
 			}
 
 			// Check if it's a Bearer token
 			parts := strings.Split(authHeader, " ")
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+				api.Unauthorized(w, "Invalid Authorization header format")
 				return
 			}
 
@@ -44,7 +48,13 @@ func Auth(authService *service.AuthService) func(http.Handler) http.Handler {
 			// Validate the token
 			claims, err := authService.ValidateToken(tokenString)
 			if err != nil {
-				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+				api.Unauthorized(w, "Invalid or expired token")
+				return
+			}
+
+			// A user with a temporary password can do nothing else until it is changed
+			if claims.MustChangePassword && (r.Method != http.MethodPost || r.URL.Path != changePasswordPath) {
+				api.Error(w, http.StatusForbidden, "password_change_required", "Change your password, then log in again")
 				return
 			}
 
@@ -69,7 +79,7 @@ func RequireRole(roles ...models.UserRole) func(http.Handler) http.Handler {
 			// Get the role from context
 			roleValue := r.Context().Value(UserRoleKey)
 			if roleValue == nil {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				api.Unauthorized(w, "Unauthorized")
 				return
 			}
 
@@ -85,7 +95,7 @@ func RequireRole(roles ...models.UserRole) func(http.Handler) http.Handler {
 			}
 
 			if !allowed {
-				http.Error(w, "Forbidden", http.StatusForbidden)
+				api.Forbidden(w, "Forbidden")
 				return
 			}
 
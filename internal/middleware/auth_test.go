@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/service"
+)
+
+const testSecret = "test-secret"
+
+func newTestAuthService(t *testing.T) *service.AuthService {
+	t.Helper()
+	authService, err := service.NewAuthService(nil, service.JWTConfig{Secret: testSecret, ExpiresIn: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authService
+}
+
+func TestAuthMustChangePassword(t *testing.T) {
+	authService := newTestAuthService(t)
+
+	token := func(mustChange bool) string {
+		claims := &service.Claims{
+			UserID:             "8d4f6a2e-3c1b-4e7a-9f0d-2b5c8e1a7d3f",
+			Role:               string(models.RoleKitchen),
+			MustChangePassword: mustChange,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	handler := Auth(authService)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		mustChange bool
+		method     string
+		path       string
+		want       int
+	}{
+		{"flagged user changes password", true, http.MethodPost, "/auth/password", http.StatusNoContent},
+		{"flagged user lists orders", true, http.MethodGet, "/orders", http.StatusForbidden},
+		{"flagged user reads password route", true, http.MethodGet, "/auth/password", http.StatusForbidden},
+		{"other user lists orders", false, http.MethodGet, "/orders", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token(tt.mustChange))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
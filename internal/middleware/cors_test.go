@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	handler := CORS([]string{"https://pos.example.com"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		want        int
+		allowOrigin string
+	}{
+		{"preflight from allowed origin", http.MethodOptions, "https://pos.example.com", true, http.StatusNoContent, "https://pos.example.com"},
+		{"preflight from other origin", http.MethodOptions, "https://evil.example.com", true, http.StatusForbidden, ""},
+		{"request from allowed origin", http.MethodGet, "https://pos.example.com", false, http.StatusTeapot, "https://pos.example.com"},
+		{"request from other origin", http.MethodGet, "https://evil.example.com", false, http.StatusTeapot, ""},
+		{"options without preflight headers", http.MethodOptions, "", false, http.StatusTeapot, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/orders", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+		})
+	}
+}
+
+func TestAuthRejectsUnauthenticatedOptions(t *testing.T) {
+	authService := newTestAuthService(t)
+	handler := Auth(authService)(RequireRole("admin")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/audit", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter allows a fixed number of attempts per key within a time window
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time // Replaced in tests
+
+	mu        sync.Mutex
+	counts    map[string]*rateWindow
+	lastSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter creates a rate limiter allowing limit attempts per key per window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:     limit,
+		window:    window,
+		now:       time.Now,
+		counts:    make(map[string]*rateWindow),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow records an attempt for key and reports whether it is within the limit
+func (l *RateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	// Drop expired windows so the map doesn't grow without bound
+	if now.Sub(l.lastSweep) > l.window {
+		for k, w := range l.counts {
+			if now.Sub(w.start) >= l.window {
+				delete(l.counts, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.counts[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		l.counts[key] = &rateWindow{start: now, count: 1}
+		return true
+	}
+
+	w.count++
+	return w.count <= l.limit
+}
+
+// Window returns the limiter's window, for Retry-After headers
+func (l *RateLimiter) Window() time.Duration {
+	return l.window
+}
+
+// ClientIP returns the remote IP address of a request
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
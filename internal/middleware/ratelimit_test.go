@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	type attempt struct {
+		after time.Duration // Since start
+		key   string
+		want  bool
+	}
+	tests := []struct {
+		name     string
+		attempts []attempt
+	}{
+		{"up to the limit", []attempt{
+			{0, "a", true}, {time.Second, "a", true}, {2 * time.Second, "a", true},
+		}},
+		{"rapid attempts past the limit", []attempt{
+			{0, "a", true}, {0, "a", true}, {0, "a", true}, {0, "a", false}, {0, "a", false},
+		}},
+		{"keys are counted apart", []attempt{
+			{0, "a", true}, {0, "a", true}, {0, "a", true}, {0, "a", false}, {0, "b", true},
+		}},
+		{"window resets", []attempt{
+			{0, "a", true}, {0, "a", true}, {0, "a", true}, {0, "a", false},
+			{time.Minute - time.Second, "a", false}, {time.Minute, "a", true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(3, time.Minute)
+			for i, a := range tt.attempts {
+				l.now = func() time.Time { return start.Add(a.after) }
+				if got := l.Allow(a.key); got != a.want {
+					t.Errorf("attempt %d for %q at +%s: Allow() = %v, want %v", i, a.key, a.after, got, a.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterSweepsExpiredKeys(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(3, time.Minute)
+	l.lastSweep = start
+
+	l.now = func() time.Time { return start }
+	l.Allow("old")
+	l.now = func() time.Time { return start.Add(50 * time.Second) }
+	l.Allow("recent")
+
+	// The next attempt past a window since the last sweep drops windows that have ended
+	l.now = func() time.Time { return start.Add(61 * time.Second) }
+	l.Allow("new")
+
+	if _, ok := l.counts["old"]; ok {
+		t.Error("expired key was not swept")
+	}
+	for _, key := range []string{"recent", "new"} {
+		if _, ok := l.counts[key]; !ok {
+			t.Errorf("live key %q was swept", key)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pizza-nz/restaurant-service/internal/api"
+)
+
+// Timeout bounds each request's context to d, so a slow query is cancelled instead of
+// holding a database connection. Transactions begun with the context roll back when it
+// expires. A handler that fails with a 500 after the deadline passed answers 503 instead.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutResponseWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+		})
+	}
+}
+
+// timeoutResponseWriter replaces an internal error caused by the request deadline with a 503
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+	ctx      context.Context
+	timedOut bool
+}
+
+// WriteHeader swaps a 500 written after the deadline for a 503 timeout response
+func (tw *timeoutResponseWriter) WriteHeader(code int) {
+	if code == http.StatusInternalServerError && errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedOut = true
+		api.Error(tw.ResponseWriter, http.StatusServiceUnavailable, "timeout", "The request took too long, please try again")
+		return
+	}
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+// Write drops the handler's own body once the timeout response has been written
+func (tw *timeoutResponseWriter) Write(b []byte) (int, error) {
+	if tw.timedOut {
+		return len(b), nil
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (tw *timeoutResponseWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
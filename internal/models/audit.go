@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog records a change made to a table row
+type AuditLog struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	UserID    *uuid.UUID      `db:"user_id" json:"user_id"` // Nil for system actions
+	Action    string          `db:"action" json:"action"`
+	TableName string          `db:"table_name" json:"table_name"`
+	RecordID  uuid.UUID       `db:"record_id" json:"record_id"`
+	OldValues json.RawMessage `db:"old_values" json:"old_values,omitempty"`
+	NewValues json.RawMessage `db:"new_values" json:"new_values,omitempty"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// AuditLogFilter narrows an audit log query, zero values match everything
+type AuditLogFilter struct {
+	UserID    *uuid.UUID
+	Action    string
+	TableName string
+	Start     *time.Time // Inclusive
+	End       *time.Time // Exclusive
+	Limit     int
+	Offset    int
+}
+
+// AuditLogPage is one page of audit log entries, newest first
+type AuditLogPage struct {
+	Entries []AuditLog `json:"entries"`
+	Total   int        `json:"total"` // Entries matching the filter across all pages
+	Limit   int        `json:"limit"`
+	Offset  int        `json:"offset"`
+}
@@ -1,36 +1,80 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// AdjustmentType represents how a modifier option adjusts the item price
+type AdjustmentType string
+
+const (
+	AdjustmentTypeAbsolute AdjustmentType = "absolute"
+	AdjustmentTypePercent  AdjustmentType = "percent"
+)
+
+// MenuItemUnit is the unit a menu item is sold in
+type MenuItemUnit string
+
+const (
+	MenuItemUnitEach MenuItemUnit = "each"
+	MenuItemUnitKg   MenuItemUnit = "kg"
+)
+
+// AllowsQuantity reports whether quantity can be ordered in this unit,
+// whole numbers for each and up to three decimal places (grams) for kg
+func (u MenuItemUnit) AllowsQuantity(quantity float64) bool {
+	if quantity <= 0 {
+		return false
+	}
+	if u == MenuItemUnitKg {
+		return math.Abs(quantity*1000-math.Round(quantity*1000)) < 1e-6
+	}
+	return quantity == math.Trunc(quantity)
+}
+
+// LineTotal returns the price of quantity units at unitPrice, rounded to the currency's minor units
+func LineTotal(unitPrice, quantity float64) float64 {
+	return RoundMoney(unitPrice * quantity)
+}
+
 // MenuCategory represents a menu category
 type MenuCategory struct {
-	ID           uuid.UUID `db:"id" json:"id"`
-	Name         string    `db:"name" json:"name"`
-	DisplayOrder int       `db:"display_order" json:"display_order"`
-	ColorCode    *string   `db:"color_code" json:"color_code"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	ID               uuid.UUID  `db:"id" json:"id"`
+	Name             string     `db:"name" json:"name"`
+	DisplayOrder     int        `db:"display_order" json:"display_order"`
+	ColorCode        *string    `db:"color_code" json:"color_code"`
+	DefaultStationID *uuid.UUID `db:"default_station_id" json:"default_station_id"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
 }
 
 // MenuItem represents a menu item
 type MenuItem struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	CategoryID  uuid.UUID `db:"category_id" json:"category_id"`
-	Name        string    `db:"name" json:"name"`
-	Price       float64   `db:"price" json:"price"`
-	Available   bool      `db:"available" json:"available"`
-	Description *string   `db:"description" json:"description"`
-	ImagePath   *string   `db:"image_path" json:"image_path"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID             uuid.UUID    `db:"id" json:"id"`
+	CategoryID     uuid.UUID    `db:"category_id" json:"category_id"`
+	Name           string       `db:"name" json:"name"`
+	Price          float64      `db:"price" json:"price"` // Per kg for weighed items
+	Unit           MenuItemUnit `db:"unit" json:"unit"`
+	Available      bool         `db:"available" json:"available"`
+	IsFeatured     bool         `db:"is_featured" json:"is_featured"`
+	OrderingWeight int          `db:"ordering_weight" json:"ordering_weight"` // Higher sorts first among featured items
+	Description    *string      `db:"description" json:"description"`
+	ImagePath      *string      `db:"image_path" json:"image_path"`
+	KitchenNotes   *string      `db:"kitchen_notes" json:"kitchen_notes"` // For stations only, not customers
+	PLU            *string      `db:"plu" json:"plu"`                     // Code integrations refer to the item by
+	DeletedAt      *time.Time   `db:"deleted_at" json:"deleted_at,omitempty"`
+	CreatedBy      *uuid.UUID   `db:"created_by" json:"created_by,omitempty"`
+	UpdatedBy      *uuid.UUID   `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at" json:"updated_at"`
 
 	// These fields are not stored in the database directly
 	Category  *MenuCategory      `db:"-" json:"category,omitempty"`
 	Modifiers []MenuItemModifier `db:"-" json:"modifiers,omitempty"`
+	Tags      []string           `db:"-" json:"tags"`
 }
 
 // Modifier represents a modifier group
@@ -38,8 +82,13 @@ type Modifier struct {
 	ID         uuid.UUID `db:"id" json:"id"`
 	Name       string    `db:"name" json:"name"`
 	IsMultiple bool      `db:"is_multiple" json:"is_multiple"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	// MinSelections and MaxSelections bound how many options can be chosen, a nil max means no limit
+	MinSelections int        `db:"min_selections" json:"min_selections"`
+	MaxSelections *int       `db:"max_selections" json:"max_selections"`
+	CreatedBy     *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	UpdatedBy     *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
 
 	// Not stored directly in the database
 	Options []ModifierOption `db:"-" json:"options,omitempty"`
@@ -47,12 +96,21 @@ type Modifier struct {
 
 // ModifierOption represents an option within a modifier group
 type ModifierOption struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	ModifierID      uuid.UUID `db:"modifier_id" json:"modifier_id"`
-	Name            string    `db:"name" json:"name"`
-	PriceAdjustment float64   `db:"price_adjustment" json:"price_adjustment"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	ID              uuid.UUID      `db:"id" json:"id"`
+	ModifierID      uuid.UUID      `db:"modifier_id" json:"modifier_id"`
+	Name            string         `db:"name" json:"name"`
+	PriceAdjustment float64        `db:"price_adjustment" json:"price_adjustment"`
+	AdjustmentType  AdjustmentType `db:"adjustment_type" json:"adjustment_type"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// AdjustmentFor returns the amount this option adds to an item with the given base price
+func (o ModifierOption) AdjustmentFor(basePrice float64) float64 {
+	if o.AdjustmentType == AdjustmentTypePercent {
+		return RoundMoney(basePrice * o.PriceAdjustment / 100)
+	}
+	return RoundMoney(o.PriceAdjustment)
 }
 
 // MenuItemModifier represents the association between a menu item and a modifier
@@ -67,21 +125,212 @@ type MenuItemModifier struct {
 	Modifier *Modifier `db:"-" json:"modifier,omitempty"`
 }
 
+// ModifierFilter pages through modifiers matching a name and selection type
+type ModifierFilter struct {
+	Query    string // Case-insensitive name substring, empty matches all
+	Multiple *bool  // Only multi-select (true) or single-select (false) modifiers, nil for both
+	Limit    int
+	Offset   int
+}
+
+// ModifierPage is one page of modifiers with their options
+type ModifierPage struct {
+	Modifiers []Modifier `json:"modifiers"`
+	Total     int        `json:"total"` // Modifiers matching across all pages
+	Limit     int        `json:"limit"`
+	Offset    int        `json:"offset"`
+}
+
+// ModifierUsage is a menu item a modifier is attached to. A modifier can't be deleted while
+// any item uses it, deleted ones included.
+type ModifierUsage struct {
+	MenuItemID uuid.UUID  `db:"menu_item_id" json:"menu_item_id"`
+	Name       string     `db:"name" json:"name"`
+	Required   bool       `db:"required" json:"required"`
+	DeletedAt  *time.Time `db:"deleted_at" json:"deleted_at,omitempty"` // Set for a deleted item, soft deletes leave its modifiers attached
+}
+
+// SelectionBounds returns how many options must be chosen from this modifier on the item.
+// Required modifiers need at least one, single-select ones allow at most one and a
+// max of 0 means there is no limit.
+func (m MenuItemModifier) SelectionBounds() (least, most int) {
+	if m.Modifier != nil {
+		least = m.Modifier.MinSelections
+		if m.Modifier.MaxSelections != nil {
+			most = *m.Modifier.MaxSelections
+		} else if !m.Modifier.IsMultiple {
+			most = 1
+		}
+	}
+	if m.Required && least < 1 {
+		least = 1
+	}
+	return least, most
+}
+
 // MenuCategoryRequest is used for category creation/update
 type MenuCategoryRequest struct {
-	Name         string  `json:"name" validate:"required,min=1,max=50"`
-	DisplayOrder int     `json:"display_order"`
-	ColorCode    *string `json:"color_code" validate:"omitempty,len=7"`
+	Name             string     `json:"name" validate:"required,min=1,max=50"`
+	DisplayOrder     int        `json:"display_order"`
+	ColorCode        *string    `json:"color_code" validate:"omitempty,len=7"`
+	DefaultStationID *uuid.UUID `json:"default_station_id"`
+}
+
+// MenuCategoryMergeRequest is used for merging one category into another
+type MenuCategoryMergeRequest struct {
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
 }
 
 // MenuItemRequest is used for menu item creation/update
 type MenuItemRequest struct {
-	CategoryID  uuid.UUID   `json:"category_id" validate:"required"`
-	Name        string      `json:"name" validate:"required,min=1,max=100"`
-	Price       float64     `json:"price" validate:"required,gte=0"`
-	Available   bool        `json:"available"`
-	Description *string     `json:"description"`
-	ImagePath   *string     `json:"image_path"`
-	ModifierIDs []uuid.UUID `json:"modifier_ids"`
-	StationID   string      `json:"station_id" validate:"required"`
+	CategoryID          uuid.UUID    `json:"category_id" validate:"required"`
+	Name                string       `json:"name" validate:"required,min=1,max=100"`
+	Price               float64      `json:"price" validate:"required,gte=0"`
+	Unit                MenuItemUnit `json:"unit" validate:"omitempty,oneof=each kg"` // Defaults to each
+	Available           bool         `json:"available"`
+	IsFeatured          bool         `json:"is_featured"`
+	OrderingWeight      int          `json:"ordering_weight"`
+	Description         *string      `json:"description"`
+	ImagePath           *string      `json:"image_path"`
+	KitchenNotes        *string      `json:"kitchen_notes" validate:"omitempty,max=500"`
+	PLU                 *string      `json:"plu" validate:"omitempty,max=32"`
+	ModifierIDs         []uuid.UUID  `json:"modifier_ids"`
+	RequiredModifierIDs []uuid.UUID  `json:"required_modifier_ids"` // Subset of ModifierIDs that must be chosen
+	StationID           string       `json:"station_id"`            // Falls back to the category's default station when empty
+	Tags                []string     `json:"tags"`                  // Replaces the item's tags on update
+}
+
+// MenuItemPatchRequest is used for partial menu item updates, nil fields are left unchanged
+type MenuItemPatchRequest struct {
+	CategoryID          *uuid.UUID    `json:"category_id"`
+	Name                *string       `json:"name" validate:"omitempty,min=1,max=100"`
+	Price               *float64      `json:"price" validate:"omitempty,gte=0"`
+	Unit                *MenuItemUnit `json:"unit" validate:"omitempty,oneof=each kg"`
+	Available           *bool         `json:"available"`
+	IsFeatured          *bool         `json:"is_featured"`
+	OrderingWeight      *int          `json:"ordering_weight"`
+	Description         *string       `json:"description"`
+	ImagePath           *string       `json:"image_path"`
+	KitchenNotes        *string       `json:"kitchen_notes" validate:"omitempty,max=500"`
+	PLU                 *string       `json:"plu" validate:"omitempty,max=32"` // An empty string clears it
+	ModifierIDs         *[]uuid.UUID  `json:"modifier_ids"`                    // Replaces the item's modifiers when present
+	RequiredModifierIDs *[]uuid.UUID  `json:"required_modifier_ids"`           // Subset of the item's modifiers that must be chosen
+	StationID           *uuid.UUID    `json:"station_id"`                      // Moves the item's routing when present
+	Tags                *[]string     `json:"tags"`                            // Replaces the item's tags when present
+}
+
+// ModifierRequest is used for modifier creation/update, options replace the existing ones
+type ModifierRequest struct {
+	Name          string                  `json:"name" validate:"required,min=1,max=100"`
+	IsMultiple    bool                    `json:"is_multiple"`
+	MinSelections int                     `json:"min_selections" validate:"gte=0"`
+	MaxSelections *int                    `json:"max_selections" validate:"omitempty,gte=1"` // Nil for no limit, forced to 1 unless is_multiple
+	Options       []ModifierOptionRequest `json:"options"`
+}
+
+// ModifierOptionRequest is one option of a ModifierRequest
+type ModifierOptionRequest struct {
+	Name            string         `json:"name" validate:"required,min=1,max=100"`
+	PriceAdjustment float64        `json:"price_adjustment"`
+	AdjustmentType  AdjustmentType `json:"adjustment_type" validate:"omitempty,oneof=absolute percent"` // Defaults to absolute
+}
+
+// MenuItemAvailabilityRequest is used for marking a menu item available or 86'd
+type MenuItemAvailabilityRequest struct {
+	Available *bool `json:"available" validate:"required"`
+}
+
+// ItemAvailability is the payload of an item.availability message
+type ItemAvailability struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Available bool      `json:"available"`
+}
+
+// PriceRounding is the step adjusted prices are rounded to
+type PriceRounding string
+
+const (
+	PriceRoundingCent       PriceRounding = "cent"        // The currency's minor unit
+	PriceRoundingTenCents   PriceRounding = "ten_cents"   // Nearest 0.10
+	PriceRoundingFiftyCents PriceRounding = "fifty_cents" // Nearest 0.50
+	PriceRoundingWhole      PriceRounding = "whole"       // Nearest whole unit
+)
+
+// Round rounds price to the nearest step, half away from zero, or returns false for an unknown rule
+func (r PriceRounding) Round(price float64) (float64, bool) {
+	var step float64
+	switch r {
+	case PriceRoundingCent:
+		return RoundMoney(price), true
+	case PriceRoundingTenCents:
+		step = 0.1
+	case PriceRoundingFiftyCents:
+		step = 0.5
+	case PriceRoundingWhole:
+		step = 1
+	default:
+		return 0, false
+	}
+	return RoundMoney(math.Round(price/step) * step), true
+}
+
+// MenuPriceAdjustRequest is used for raising or lowering prices by a percentage
+type MenuPriceAdjustRequest struct {
+	CategoryID *uuid.UUID    `json:"category_id"` // Required unless All is set
+	All        bool          `json:"all"`         // Adjust every menu item
+	Percent    float64       `json:"percent" validate:"required,gte=-50,lte=100"`
+	Rounding   PriceRounding `json:"rounding" validate:"omitempty,oneof=cent ten_cents fifty_cents whole"` // Defaults to cent
+}
+
+// Apply returns price adjusted by the request's percentage and rounding
+func (r MenuPriceAdjustRequest) Apply(price float64) float64 {
+	adjusted, _ := r.Rounding.Round(price * (1 + r.Percent/100))
+	return max(adjusted, 0)
+}
+
+// MenuPriceChange is one menu item's price before and after an adjustment
+type MenuPriceChange struct {
+	MenuItemID uuid.UUID `db:"id" json:"menu_item_id"`
+	Name       string    `db:"name" json:"name"`
+	OldPrice   float64   `db:"price" json:"old_price"`
+	NewPrice   float64   `db:"-" json:"new_price"`
+}
+
+// MenuPriceAdjustResult reports the outcome of a bulk price adjustment
+type MenuPriceAdjustResult struct {
+	CategoryID *uuid.UUID        `json:"category_id,omitempty"`
+	Percent    float64           `json:"percent"`
+	Rounding   PriceRounding     `json:"rounding"`
+	Changes    []MenuPriceChange `json:"changes"`
+}
+
+// MenuItemPriceChange is one recorded change to a menu item's price
+type MenuItemPriceChange struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	MenuItemID uuid.UUID  `db:"menu_item_id" json:"menu_item_id"`
+	OldPrice   float64    `db:"old_price" json:"old_price"`
+	NewPrice   float64    `db:"new_price" json:"new_price"`
+	ChangedBy  *uuid.UUID `db:"changed_by" json:"changed_by"`
+	ChangedAt  time.Time  `db:"changed_at" json:"changed_at"`
+}
+
+// MenuItemFilter narrows a menu item listing, zero values match everything
+type MenuItemFilter struct {
+	CategoryID    *uuid.UUID
+	Tag           string
+	Featured      *bool
+	FeaturedFirst bool // Sort featured items first, by ordering weight, before the rest by name
+}
+
+// MenuItemBulkDeleteRequest is used for deleting several menu items at once
+type MenuItemBulkDeleteRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1"`
+}
+
+// MenuItemDeleteResult reports the outcome of deleting a single menu item
+type MenuItemDeleteResult struct {
+	ID      uuid.UUID `json:"id"`
+	Deleted bool      `json:"deleted"`
+	Reason  string    `json:"reason,omitempty"`
 }
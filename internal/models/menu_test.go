@@ -0,0 +1,36 @@
+package models
+
+import "testing"
+
+func TestMenuPriceAdjustRequestApply(t *testing.T) {
+	tests := []struct {
+		price    float64
+		rounding PriceRounding
+		want     float64
+	}{
+		{12.50, PriceRoundingCent, 13.13}, // 13.125 rounds half away from zero
+		{12.50, PriceRoundingTenCents, 13.10},
+		{12.50, PriceRoundingFiftyCents, 13.00},
+		{12.50, PriceRoundingWhole, 13.00},
+		{9.90, PriceRoundingCent, 10.40}, // 10.395
+		{9.90, PriceRoundingFiftyCents, 10.50},
+		{9.90, PriceRoundingWhole, 10.00},
+		{18.99, PriceRoundingCent, 19.94}, // 19.9395
+		{18.99, PriceRoundingTenCents, 19.90},
+		{18.99, PriceRoundingFiftyCents, 20.00},
+		{4.00, PriceRoundingCent, 4.20},
+		{4.00, PriceRoundingFiftyCents, 4.00}, // Rounds back to the old price
+		{0, PriceRoundingCent, 0},
+	}
+	for _, tt := range tests {
+		req := MenuPriceAdjustRequest{Percent: 5, Rounding: tt.rounding}
+		if got := req.Apply(tt.price); ToMinor(got) != ToMinor(tt.want) {
+			t.Errorf("Apply(%v) with %s rounding = %v, want %v", tt.price, tt.rounding, got, tt.want)
+		}
+	}
+
+	lower := MenuPriceAdjustRequest{Percent: -50, Rounding: PriceRoundingWhole}
+	if got := lower.Apply(0.80); got != 0 {
+		t.Errorf("Apply(0.80) at -50%% = %v, want 0", got)
+	}
+}
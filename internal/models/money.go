@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// ErrInvalidMinorUnits is returned for a currency precision the money columns can't store
+var ErrInvalidMinorUnits = errors.New("currency minor units must be between 0 and 2")
+
+// minorUnits is how many decimal places the currency has, prices are stored as DECIMAL(10, 2)
+var minorUnits = 2
+
+// SetCurrencyMinorUnits sets the currency's decimal places, 2 for cents or 0 for whole units.
+// Call it once at startup before any prices are computed.
+func SetCurrencyMinorUnits(units int) error {
+	if units < 0 || units > 2 {
+		return ErrInvalidMinorUnits
+	}
+	minorUnits = units
+	return nil
+}
+
+// ToMinor converts an amount to a whole number of minor units (cents), rounding half away from zero
+func ToMinor(amount float64) int64 {
+	return int64(math.Round(amount * math.Pow10(minorUnits)))
+}
+
+// FromMinor converts a number of minor units back to an amount
+func FromMinor(minor int64) float64 {
+	return float64(minor) / math.Pow10(minorUnits)
+}
+
+// RoundMoney rounds an amount to the currency's minor units
+func RoundMoney(amount float64) float64 {
+	return FromMinor(ToMinor(amount))
+}
+
+// FormatMoney formats an amount with exactly the currency's minor units, e.g. 12.50
+func FormatMoney(amount float64) string {
+	return strconv.FormatFloat(RoundMoney(amount), 'f', minorUnits, 64)
+}
+
+// AddMoney sums amounts in minor units so repeated additions don't drift
+func AddMoney(amounts ...float64) float64 {
+	var minor int64
+	for _, amount := range amounts {
+		minor += ToMinor(amount)
+	}
+	return FromMinor(minor)
+}
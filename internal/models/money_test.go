@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoneyHelpers(t *testing.T) {
+	t.Cleanup(func() { _ = SetCurrencyMinorUnits(2) })
+
+	tests := []struct {
+		units     int
+		amount    float64
+		minor     int64
+		formatted string
+	}{
+		{2, 0, 0, "0.00"},
+		{2, 12.5, 1250, "12.50"},
+		{2, 0.125, 13, "0.13"}, // Half a cent rounds away from zero
+		{2, -0.125, -13, "-0.13"},
+		{2, 0.124, 12, "0.12"},
+		{0, 12, 12, "12"},
+		{0, 2.5, 3, "3"}, // Half a whole unit rounds away from zero
+		{0, -2.5, -3, "-3"},
+		{0, 2.49, 2, "2"},
+	}
+	for _, tt := range tests {
+		if err := SetCurrencyMinorUnits(tt.units); err != nil {
+			t.Fatalf("SetCurrencyMinorUnits(%d): %v", tt.units, err)
+		}
+		if got := ToMinor(tt.amount); got != tt.minor {
+			t.Errorf("%d units: ToMinor(%v) = %d, want %d", tt.units, tt.amount, got, tt.minor)
+		}
+		if got := FormatMoney(tt.amount); got != tt.formatted {
+			t.Errorf("%d units: FormatMoney(%v) = %q, want %q", tt.units, tt.amount, got, tt.formatted)
+		}
+	}
+}
+
+func TestLineTotal(t *testing.T) {
+	t.Cleanup(func() { _ = SetCurrencyMinorUnits(2) })
+
+	tests := []struct {
+		units               int
+		unitPrice, quantity float64
+		want                string
+	}{
+		{2, 4.50, 3, "13.50"},
+		{2, 2.50, 0.345, "0.86"}, // 0.8625 per weighed kilo
+		{2, 0.25, 0.5, "0.13"},   // Exactly half a cent
+		{0, 250, 0.345, "86"},
+		{0, 5, 0.5, "3"},
+		{0, 0, 2, "0"},
+	}
+	for _, tt := range tests {
+		if err := SetCurrencyMinorUnits(tt.units); err != nil {
+			t.Fatalf("SetCurrencyMinorUnits(%d): %v", tt.units, err)
+		}
+		if got := FormatMoney(LineTotal(tt.unitPrice, tt.quantity)); got != tt.want {
+			t.Errorf("%d units: LineTotal(%v, %v) = %s, want %s", tt.units, tt.unitPrice, tt.quantity, got, tt.want)
+		}
+	}
+}
+
+func TestSetCurrencyMinorUnits(t *testing.T) {
+	t.Cleanup(func() { _ = SetCurrencyMinorUnits(2) })
+
+	// Prices are stored as DECIMAL(10, 2), so a third decimal place can't be kept
+	for _, units := range []int{-1, 3} {
+		if err := SetCurrencyMinorUnits(units); !errors.Is(err, ErrInvalidMinorUnits) {
+			t.Errorf("SetCurrencyMinorUnits(%d) = %v, want %v", units, err, ErrInvalidMinorUnits)
+		}
+	}
+	if got := FormatMoney(1); got != "1.00" {
+		t.Errorf("rejected units changed formatting: FormatMoney(1) = %q", got)
+	}
+
+	if err := SetCurrencyMinorUnits(0); err != nil {
+		t.Fatalf("SetCurrencyMinorUnits(0): %v", err)
+	}
+	if got := FormatMoney(1); got != "1" {
+		t.Errorf("FormatMoney(1) with 0 units = %q, want %q", got, "1")
+	}
+}
@@ -12,35 +12,168 @@ type OrderStatus string
 const (
 	OrderStatusNew        OrderStatus = "new"
 	OrderStatusInProgress OrderStatus = "in_progress"
+	OrderStatusReady      OrderStatus = "ready" // All items done, waiting to be closed
 	OrderStatusCompleted  OrderStatus = "completed"
 	OrderStatusCancelled  OrderStatus = "cancelled"
 )
 
+// OrderSource is where an order was placed
+type OrderSource string
+
+const (
+	OrderSourcePOS    OrderSource = "pos"
+	OrderSourceOnline OrderSource = "online" // Received from a third-party platform, see Order.Provider
+)
+
 // OrderItemStatus represents the status of an order item
 type OrderItemStatus string
 
 const (
 	OrderItemStatusPending    OrderItemStatus = "pending"
 	OrderItemStatusInProgress OrderItemStatus = "in_progress"
+	OrderItemStatusReady      OrderItemStatus = "ready" // Cooked, awaiting pickup
 	OrderItemStatusCompleted  OrderItemStatus = "completed"
 	OrderItemStatusCancelled  OrderItemStatus = "cancelled"
 )
 
+// orderTransitions lists the statuses each order status can move to
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusNew:        {OrderStatusInProgress, OrderStatusCancelled},
+	OrderStatusInProgress: {OrderStatusReady, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusReady:      {OrderStatusInProgress, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusCompleted:  {}, // Only reopened through ReopenOrder or by recalling an item
+}
+
+// orderItemTransitions lists the statuses each order item status can move to
+var orderItemTransitions = map[OrderItemStatus][]OrderItemStatus{
+	OrderItemStatusPending:    {OrderItemStatusInProgress, OrderItemStatusCancelled},
+	OrderItemStatusInProgress: {OrderItemStatusReady, OrderItemStatusCompleted, OrderItemStatusCancelled},
+	OrderItemStatusReady:      {OrderItemStatusInProgress, OrderItemStatusCompleted, OrderItemStatusCancelled},
+	OrderItemStatusCompleted:  {OrderItemStatusInProgress}, // Served items can't be voided
+}
+
+// IsValid reports whether s is a known order status
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok || s == OrderStatusCancelled
+}
+
+// CanTransitionTo reports whether an order can move from this status to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid reports whether s is a known order item status
+func (s OrderItemStatus) IsValid() bool {
+	_, ok := orderItemTransitions[s]
+	return ok || s == OrderItemStatusCancelled
+}
+
+// CanTransitionTo reports whether an order item can move from this status to next
+func (s OrderItemStatus) CanTransitionTo(next OrderItemStatus) bool {
+	for _, allowed := range orderItemTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemsDone reports whether an order with items in these statuses is finished: every item
+// was completed or cancelled and at least one completed. Ready items are still waiting to be
+// served, and an order whose items were all cancelled is left for staff to cancel.
+func ItemsDone(statuses []OrderItemStatus) bool {
+	completed := false
+	for _, status := range statuses {
+		switch status {
+		case OrderItemStatusCompleted:
+			completed = true
+		case OrderItemStatusCancelled:
+		default:
+			return false
+		}
+	}
+	return completed
+}
+
+// StationItemsGroupBy controls how station items are ordered
+type StationItemsGroupBy string
+
+const (
+	StationItemsGroupByTime  StationItemsGroupBy = "time"
+	StationItemsGroupByOrder StationItemsGroupBy = "order"
+)
+
 // Order represents a customer order
 type Order struct {
-	ID          uuid.UUID   `db:"id" json:"id"`
-	UserID      uuid.UUID   `db:"user_id" json:"user_id"`
-	OrderNumber string      `db:"order_number" json:"order_number"`
-	Status      OrderStatus `db:"status" json:"status"`
-	Total       float64     `db:"total" json:"total"`
-	OrderedAt   time.Time   `db:"ordered_at" json:"ordered_at"`
-	CompletedAt *time.Time  `db:"completed_at" json:"completed_at"`
-	CreatedAt   time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time   `db:"updated_at" json:"updated_at"`
+	ID            uuid.UUID   `db:"id" json:"id"`
+	UserID        uuid.UUID   `db:"user_id" json:"user_id"`
+	OrderNumber   string      `db:"order_number" json:"order_number"`
+	Status        OrderStatus `db:"status" json:"status"`
+	Total         float64     `db:"total" json:"total"`
+	Notes         *string     `db:"notes" json:"notes"`
+	OrderedAt     time.Time   `db:"ordered_at" json:"ordered_at"`
+	FiredAt       *time.Time  `db:"fired_at" json:"fired_at"` // When a station first started on one of its items
+	CompletedAt   *time.Time  `db:"completed_at" json:"completed_at"`
+	IsRush        bool        `db:"is_rush" json:"is_rush"`
+	HeldAt        *time.Time  `db:"held_at" json:"held_at,omitempty"`         // When the order was last put on hold
+	ReleasedAt    *time.Time  `db:"released_at" json:"released_at,omitempty"` // When the last hold was released
+	CustomerName  *string     `db:"customer_name" json:"customer_name"`
+	CustomerPhone *string     `db:"customer_phone" json:"customer_phone"`
+	Source        OrderSource `db:"source" json:"source"`
+	Provider      *string     `db:"provider" json:"provider,omitempty"`       // Platform an online order came from
+	ExternalID    *string     `db:"external_id" json:"external_id,omitempty"` // The platform's own order ID
+	CreatedBy     *uuid.UUID  `db:"created_by" json:"created_by,omitempty"`
+	UpdatedBy     *uuid.UUID  `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt     time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time   `db:"updated_at" json:"updated_at"`
 
 	// Not stored directly in the database
 	Items []OrderItem `db:"-" json:"items,omitempty"`
 	User  *User       `db:"-" json:"user,omitempty"`
+
+	// Derived from Items, only set when the items are loaded
+	ItemsTotal     *int     `db:"-" json:"items_total,omitempty"`
+	ItemsCompleted *int     `db:"-" json:"items_completed,omitempty"` // Ready or completed
+	Progress       *float64 `db:"-" json:"progress,omitempty"`        // ItemsCompleted / ItemsTotal, 0 to 1
+}
+
+// IsOpen reports whether the order can still be changed
+func (o Order) IsOpen() bool {
+	return o.Status != OrderStatusCompleted && o.Status != OrderStatusCancelled
+}
+
+// IsHeld reports whether the order is on hold, keeping its items off the station feeds
+func (o Order) IsHeld() bool {
+	return o.HeldAt != nil && o.ReleasedAt == nil
+}
+
+// SetProgress fills in the item counts and progress from the loaded items, ignoring cancelled ones
+func (o *Order) SetProgress() {
+	total, done := 0, 0
+	for _, item := range o.Items {
+		switch item.Status {
+		case OrderItemStatusCancelled:
+			continue
+		case OrderItemStatusReady, OrderItemStatusCompleted:
+			done++
+		}
+		total++
+	}
+
+	progress := 0.0
+	if total > 0 {
+		progress = float64(done) / float64(total)
+	}
+
+	o.ItemsTotal = &total
+	o.ItemsCompleted = &done
+	o.Progress = &progress
 }
 
 // OrderItem represents an item in an order
@@ -49,46 +182,182 @@ type OrderItem struct {
 	OrderID             uuid.UUID       `db:"order_id" json:"order_id"`
 	MenuItemID          uuid.UUID       `db:"menu_item_id" json:"menu_item_id"`
 	StationID           uuid.UUID       `db:"station_id" json:"station_id"`
-	Quantity            int             `db:"quantity" json:"quantity"`
+	Quantity            float64         `db:"quantity" json:"quantity"` // Kilograms for weighed items
 	Price               float64         `db:"price" json:"price"`
 	Status              OrderItemStatus `db:"status" json:"status"`
 	SpecialInstructions *string         `db:"special_instructions" json:"special_instructions"`
+	Seat                *int            `db:"seat" json:"seat"` // Nil when unassigned
 	SentToStationAt     *time.Time      `db:"sent_to_station_at" json:"sent_to_station_at"`
 	CompletedAt         *time.Time      `db:"completed_at" json:"completed_at"`
 	CreatedAt           time.Time       `db:"created_at" json:"created_at"`
 	UpdatedAt           time.Time       `db:"updated_at" json:"updated_at"`
 
 	// Not stored directly in the database
-	Name      string              `db:"-" json:"name"`
-	Modifiers []OrderItemModifier `db:"-" json:"modifiers,omitempty"`
-	Station   *Station            `db:"-" json:"station,omitempty"`
+	Name         string              `db:"name" json:"name"`
+	OrderNumber  string              `db:"order_number" json:"order_number,omitempty"`         // Only set on station feeds
+	IsRush       bool                `db:"is_rush" json:"is_rush,omitempty"`                   // The item or its order is rushed, only set on station feeds and single items
+	KitchenNotes *string             `db:"kitchen_notes" json:"kitchen_notes,omitempty"`       // Only set on station feeds
+	Priority     *int                `db:"display_priority" json:"display_priority,omitempty"` // Manual feed position, only set on station feeds
+	Modifiers    []OrderItemModifier `db:"-" json:"modifiers,omitempty"`
+	Station      *Station            `db:"-" json:"station,omitempty"`
 }
 
 // OrderItemModifier represents a modifier applied to an order item
 type OrderItemModifier struct {
-	ID               uuid.UUID `db:"id" json:"id"`
-	OrderItemID      uuid.UUID `db:"order_item_id" json:"order_item_id"`
-	ModifierOptionID uuid.UUID `db:"modifier_option_id" json:"modifier_option_id"`
-	PriceAdjustment  float64   `db:"price_adjustment" json:"price_adjustment"`
-	CreatedAt        time.Time `db:"created_at" json:"created_at"`
-
-	// Not stored directly in the database
-	Name string `db:"-" json:"name"`
+	ID               uuid.UUID  `db:"id" json:"id"`
+	OrderItemID      uuid.UUID  `db:"order_item_id" json:"order_item_id"`
+	ModifierOptionID *uuid.UUID `db:"modifier_option_id" json:"modifier_option_id"` // Nil once the option is deleted
+	Name             string     `db:"name" json:"name"`                             // Option name when ordered
+	PriceAdjustment  float64    `db:"price_adjustment" json:"price_adjustment"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
 }
 
 // OrderRequest is used for order creation
 type OrderRequest struct {
-	Items []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	Items               []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	Notes               *string            `json:"notes"`
+	CustomerName        *string            `json:"customer_name" validate:"omitempty,max=100"` // For call-ahead and delivery orders
+	CustomerPhone       *string            `json:"customer_phone" validate:"omitempty,max=20"`
+	MergeIdenticalItems bool               `json:"merge_identical_items"` // Combine lines with the same item, modifiers and instructions
+}
+
+// KitchenItem is an item of an order being cooked, as read for the expo view
+type KitchenItem struct {
+	OrderItem
+	StationName string      `db:"station_name"`
+	OrderStatus OrderStatus `db:"order_status"`
+	OrderedAt   time.Time   `db:"ordered_at"`
+}
+
+// KitchenStationItems is one station's share of an order in the expo view
+type KitchenStationItems struct {
+	StationID   uuid.UUID   `json:"station_id"`
+	StationName string      `json:"station_name"`
+	Items       []OrderItem `json:"items"`
+}
+
+// KitchenOrder is an order being cooked with its items grouped by station, for the expo view
+type KitchenOrder struct {
+	OrderID        uuid.UUID             `json:"order_id"`
+	OrderNumber    string                `json:"order_number"`
+	Status         OrderStatus           `json:"status"`
+	IsRush         bool                  `json:"is_rush"`
+	OrderedAt      time.Time             `json:"ordered_at"`
+	ItemsTotal     int                   `json:"items_total"`
+	ItemsCompleted int                   `json:"items_completed"` // Ready or completed
+	Progress       float64               `json:"progress"`        // ItemsCompleted / ItemsTotal, 0 to 1
+	OldestItemAge  int                   `json:"oldest_item_age"` // Seconds since the oldest unfinished item was sent, 0 when none
+	Stations       []KitchenStationItems `json:"stations"`
+}
+
+// OrderTotalRecalculation reports an order total before and after recalculation
+type OrderTotalRecalculation struct {
+	OrderID       uuid.UUID `json:"order_id"`
+	PreviousTotal float64   `json:"previous_total"`
+	Total         float64   `json:"total"`
+	Changed       bool      `json:"changed"`
+}
+
+// OrderUpdate is the payload of an order.update message. The ID is always set so clients
+// can refetch; the other fields let them patch their state without a request.
+type OrderUpdate struct {
+	UpdateType string          `json:"update_type"`
+	ID         uuid.UUID       `json:"id"`
+	Status     OrderStatus     `json:"status,omitempty"`
+	IsRush     *bool           `json:"is_rush,omitempty"`
+	IsHeld     *bool           `json:"is_held,omitempty"`
+	Total      *float64        `json:"total,omitempty"`
+	ItemID     *uuid.UUID      `json:"item_id,omitempty"`
+	ItemStatus OrderItemStatus `json:"item_status,omitempty"`
+	StationID  *uuid.UUID      `json:"station_id,omitempty"`
+}
+
+// OpenOrderSort orders the open order listing
+type OpenOrderSort string
+
+const (
+	OpenOrderSortAge    OpenOrderSort = "age"    // Oldest first
+	OpenOrderSortNewest OpenOrderSort = "newest" // Most recent first
+)
+
+// OpenOrderFilter pages through orders that are neither completed nor cancelled
+type OpenOrderFilter struct {
+	Sort   OpenOrderSort
+	Limit  int
+	Offset int
+}
+
+// OrderPage is one page of orders
+type OrderPage struct {
+	Orders []Order `json:"orders"`
+	Total  int     `json:"total"` // Orders matching across all pages
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// OrderTimelineEvent is one entry in an order's history
+type OrderTimelineEvent struct {
+	At          time.Time  `db:"at" json:"at"`
+	Event       string     `db:"event" json:"event"`
+	OrderItemID *uuid.UUID `db:"order_item_id" json:"order_item_id,omitempty"` // Nil for order-level events
+	ItemName    *string    `db:"item_name" json:"item_name,omitempty"`
+	UserID      *uuid.UUID `db:"user_id" json:"user_id"` // Nil when the actor isn't known
+	Details     *string    `db:"details" json:"details,omitempty"`
+}
+
+// OrderStatusRequest is used for changing an order's status
+type OrderStatusRequest struct {
+	Status OrderStatus `json:"status" validate:"required"`
+}
+
+// OrderReopenRequest is used for reopening an order that was completed by mistake
+type OrderReopenRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// OrderItemStatusRequest is used for changing an order item's status
+type OrderItemStatusRequest struct {
+	Status OrderItemStatus `json:"status" validate:"required"`
+	Reason string          `json:"reason"` // Recorded when the item is cancelled
+}
+
+// OrderNotesRequest is used for updating an order's notes
+type OrderNotesRequest struct {
+	Notes *string `json:"notes"`
 }
 
 // OrderItemRequest is used for order item creation
 type OrderItemRequest struct {
 	MenuItemID          uuid.UUID              `json:"menu_item_id" validate:"required"`
-	Quantity            int                    `json:"quantity" validate:"required,min=1"`
+	Quantity            float64                `json:"quantity" validate:"required,gt=0"` // Whole for each items, up to 3 decimals for kg
 	SpecialInstructions *string                `json:"special_instructions"`
+	Seat                *int                   `json:"seat" validate:"omitempty,min=1"`
 	Modifiers           []OrderModifierRequest `json:"modifiers"`
 }
 
+// OrderItemProblem describes why one item of an order request can't be ordered
+type OrderItemProblem struct {
+	Index      int       `json:"index"` // Position of the item in the request
+	MenuItemID uuid.UUID `json:"menu_item_id"`
+	Message    string    `json:"message"`
+}
+
+// InboundOrder is an order from a third-party platform, decoded from its webhook payload
+type InboundOrder struct {
+	ExternalID    string
+	CustomerName  *string
+	CustomerPhone *string
+	Notes         *string
+	Items         []InboundOrderItem
+}
+
+// InboundOrderItem is a line of an inbound order, naming the menu item by PLU
+type InboundOrderItem struct {
+	PLU                 string
+	Quantity            float64
+	SpecialInstructions *string
+}
+
 // OrderModifierRequest is used for order item modifier creation
 type OrderModifierRequest struct {
 	OptionID uuid.UUID `json:"option_id" validate:"required"`
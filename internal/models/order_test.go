@@ -0,0 +1,129 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOrderStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+		want     bool
+	}{
+		{OrderStatusNew, OrderStatusInProgress, true},
+		{OrderStatusInProgress, OrderStatusCompleted, true},
+		{OrderStatusReady, OrderStatusInProgress, true},
+		// Completed orders are only reopened through ReopenOrder, with its window and audit entry
+		{OrderStatusCompleted, OrderStatusInProgress, false},
+		{OrderStatusCompleted, OrderStatusCancelled, false},
+		{OrderStatusCancelled, OrderStatusNew, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	if !OrderStatusCompleted.IsValid() {
+		t.Error("completed should still be a valid status")
+	}
+}
+
+func TestOrderItemStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to OrderItemStatus
+		want     bool
+	}{
+		{OrderItemStatusPending, OrderItemStatusInProgress, true},
+		{OrderItemStatusPending, OrderItemStatusCancelled, true},
+		{OrderItemStatusPending, OrderItemStatusCompleted, false},
+		{OrderItemStatusInProgress, OrderItemStatusReady, true},
+		{OrderItemStatusInProgress, OrderItemStatusCompleted, true},
+		{OrderItemStatusReady, OrderItemStatusInProgress, true},
+		{OrderItemStatusReady, OrderItemStatusCompleted, true},
+		{OrderItemStatusCompleted, OrderItemStatusInProgress, true},
+		{OrderItemStatusCompleted, OrderItemStatusCancelled, false},
+		{OrderItemStatusCancelled, OrderItemStatusPending, false},
+		{OrderItemStatusCancelled, OrderItemStatusCancelled, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestItemsDone(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []OrderItemStatus
+		want     bool
+	}{
+		{"all completed", []OrderItemStatus{OrderItemStatusCompleted, OrderItemStatusCompleted}, true},
+		{"completed and cancelled", []OrderItemStatus{OrderItemStatusCompleted, OrderItemStatusCancelled}, true},
+		{"ready item waiting", []OrderItemStatus{OrderItemStatusCompleted, OrderItemStatusReady}, false},
+		{"all ready", []OrderItemStatus{OrderItemStatusReady, OrderItemStatusReady}, false},
+		{"item still cooking", []OrderItemStatus{OrderItemStatusCancelled, OrderItemStatusInProgress}, false},
+		{"all cancelled", []OrderItemStatus{OrderItemStatusCancelled, OrderItemStatusCancelled}, false},
+		{"no items", nil, false},
+	}
+	for _, tt := range tests {
+		if got := ItemsDone(tt.statuses); got != tt.want {
+			t.Errorf("%s: ItemsDone() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Station feeds filter on the same columns, (held_at IS NULL OR released_at IS NOT NULL)
+func TestOrderIsHeld(t *testing.T) {
+	held := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	released := held.Add(10 * time.Minute)
+
+	tests := []struct {
+		name  string
+		order Order
+		want  bool
+	}{
+		{"never held", Order{}, false},
+		{"on hold", Order{HeldAt: &held}, true},
+		{"released", Order{HeldAt: &held, ReleasedAt: &released}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.order.IsHeld(); got != tt.want {
+			t.Errorf("%s: IsHeld() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Create re-reads the order with GetByID, so a create response matches a later GET only
+// while every field it returns is scanned from the database. Relations and progress counts
+// are loaded separately and are listed here.
+func TestOrderFieldsScanned(t *testing.T) {
+	loadedSeparately := map[string]bool{
+		"Order.Items":          true,
+		"Order.User":           true,
+		"Order.ItemsTotal":     true,
+		"Order.ItemsCompleted": true,
+		"Order.Progress":       true,
+		"OrderItem.Modifiers":  true,
+		"OrderItem.Station":    true,
+	}
+
+	for _, v := range []interface{}{Order{}, OrderItem{}, OrderItemModifier{}} {
+		typ := reflect.TypeOf(v)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			name := typ.Name() + "." + field.Name
+			if json := field.Tag.Get("json"); json == "-" || !field.IsExported() {
+				continue
+			}
+
+			db, tagged := field.Tag.Lookup("db")
+			scanned := tagged && db != "-"
+			if scanned == loadedSeparately[name] {
+				t.Errorf("%s: db tag %q, loaded separately %v", name, db, loadedSeparately[name])
+			}
+		}
+	}
+}
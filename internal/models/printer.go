@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +17,33 @@ const (
 	PrinterTypeOther   PrinterType = "other"
 )
 
+// IsValid reports whether the printer type is known
+func (t PrinterType) IsValid() bool {
+	switch t {
+	case PrinterTypeThermal, PrinterTypeKitchen, PrinterTypeReceipt, PrinterTypeOther:
+		return true
+	}
+	return false
+}
+
+// PrinterPurpose is a kind of document a printer can be the default for
+type PrinterPurpose string
+
+const (
+	PrinterPurposeReceipt PrinterPurpose = "receipt"
+	PrinterPurposeKitchen PrinterPurpose = "kitchen"
+	PrinterPurposeLabel   PrinterPurpose = "label"
+)
+
+// IsValid reports whether the purpose is a known document type
+func (p PrinterPurpose) IsValid() bool {
+	switch p {
+	case PrinterPurposeReceipt, PrinterPurposeKitchen, PrinterPurposeLabel:
+		return true
+	}
+	return false
+}
+
 // DisplayType represents a display type
 type DisplayType string
 
@@ -25,40 +53,81 @@ const (
 	DisplayTypeOther    DisplayType = "other"
 )
 
+// DefaultPrinterCharsPerLine is the print width used when a printer has none configured
+const DefaultPrinterCharsPerLine = 42
+
+// Print widths a printer can be configured with, matching the printers table CHECK
+const (
+	MinPrinterCharsPerLine = 24
+	MaxPrinterCharsPerLine = 80
+)
+
 // Printer represents a physical printer
 type Printer struct {
-	ID        uuid.UUID   `db:"id" json:"id"`
-	Name      string      `db:"name" json:"name"`
-	Type      PrinterType `db:"type" json:"type"`
-	IPAddress *string     `db:"ip_address" json:"ip_address"`
-	Port      *int        `db:"port" json:"port"`
-	Model     *string     `db:"model" json:"model"`
-	IsDefault bool        `db:"is_default" json:"is_default"`
-	IsActive  bool        `db:"is_active" json:"is_active"`
-	CreatedAt time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time   `db:"updated_at" json:"updated_at"`
+	ID           uuid.UUID       `db:"id" json:"id"`
+	Name         string          `db:"name" json:"name"`
+	Type         PrinterType     `db:"type" json:"type"`
+	IPAddress    *string         `db:"ip_address" json:"ip_address"`
+	Port         *int            `db:"port" json:"port"`
+	Model        *string         `db:"model" json:"model"`
+	CharsPerLine int             `db:"chars_per_line" json:"chars_per_line"` // Commonly 32, 42 or 48
+	IsDefault    bool            `db:"is_default" json:"is_default"`         // Same as DefaultFor being receipt
+	DefaultFor   *PrinterPurpose `db:"default_for" json:"default_for"`       // Nil when not a default printer
+	IsActive     bool            `db:"is_active" json:"is_active"`
+	CreatedBy    *uuid.UUID      `db:"created_by" json:"created_by,omitempty"`
+	UpdatedBy    *uuid.UUID      `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// ErrInvalidDisplayOfflineAfter is returned for a display offline threshold that isn't positive
+var ErrInvalidDisplayOfflineAfter = errors.New("display offline threshold must be positive")
+
+// displayOfflineAfter is how long a display can go without a heartbeat before it counts as offline
+var displayOfflineAfter = 90 * time.Second
+
+// SetDisplayOfflineAfter sets how long a display can go without a heartbeat before it counts
+// as offline. Call it once at startup.
+func SetDisplayOfflineAfter(d time.Duration) error {
+	if d <= 0 {
+		return ErrInvalidDisplayOfflineAfter
+	}
+	displayOfflineAfter = d
+	return nil
 }
 
 // Display represents a display device
 type Display struct {
-	ID        uuid.UUID   `db:"id" json:"id"`
-	Name      string      `db:"name" json:"name"`
-	Type      DisplayType `db:"type" json:"type"`
-	IPAddress *string     `db:"ip_address" json:"ip_address"`
-	IsActive  bool        `db:"is_active" json:"is_active"`
-	CreatedAt time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time   `db:"updated_at" json:"updated_at"`
+	ID         uuid.UUID   `db:"id" json:"id"`
+	Name       string      `db:"name" json:"name"`
+	Type       DisplayType `db:"type" json:"type"`
+	IPAddress  *string     `db:"ip_address" json:"ip_address"`
+	IsActive   bool        `db:"is_active" json:"is_active"`
+	LastSeenAt *time.Time  `db:"last_seen_at" json:"last_seen_at"`
+	CreatedAt  time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time   `db:"updated_at" json:"updated_at"`
+
+	// Derived from LastSeenAt
+	Online bool `db:"-" json:"online"`
+}
+
+// IsOnline reports whether the display has sent a heartbeat recently
+func (d Display) IsOnline(now time.Time) bool {
+	return d.LastSeenAt != nil && now.Sub(*d.LastSeenAt) < displayOfflineAfter
 }
 
 // PrinterRequest is used for printer creation/update
 type PrinterRequest struct {
-	Name      string      `json:"name" validate:"required,min=1,max=100"`
-	Type      PrinterType `json:"type" validate:"required,oneof=thermal kitchen receipt other"`
-	IPAddress *string     `json:"ip_address" validate:"omitempty,ip"`
-	Port      *int        `json:"port" validate:"omitempty,min=1,max=65535"`
-	Model     *string     `json:"model"`
-	IsDefault bool        `json:"is_default"`
-	IsActive  bool        `json:"is_active"`
+	Name       string          `json:"name" validate:"required,min=1,max=100"`
+	Type       PrinterType     `json:"type" validate:"required,oneof=thermal kitchen receipt other"`
+	IPAddress  *string         `json:"ip_address" validate:"omitempty,ip"`
+	Port       *int            `json:"port" validate:"omitempty,min=1,max=65535"`
+	Model      *string         `json:"model"`
+	IsDefault  bool            `json:"is_default"` // Shorthand for default_for receipt
+	DefaultFor *PrinterPurpose `json:"default_for" validate:"omitempty,oneof=receipt kitchen label"`
+	IsActive   bool            `json:"is_active"`
+
+	CharsPerLine *int `json:"chars_per_line" validate:"omitempty,min=24,max=80"` // Unset keeps the current width, 42 for a new printer
 }
 
 // DisplayRequest is used for display creation/update
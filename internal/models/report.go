@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrepTimeStats summarises how long items took from reaching a station to completion, in seconds
+type PrepTimeStats struct {
+	StationID   *uuid.UUID `db:"station_id" json:"station_id,omitempty"` // Nil for the overall figures
+	StationName *string    `db:"station_name" json:"station_name,omitempty"`
+	ItemCount   int        `db:"item_count" json:"item_count"`
+	P50         float64    `db:"p50" json:"p50"`
+	P90         float64    `db:"p90" json:"p90"`
+	P99         float64    `db:"p99" json:"p99"`
+}
+
+// PrepTimeReport is the prep-time percentiles for a date range, overall and per station
+type PrepTimeReport struct {
+	StartDate string          `json:"start_date"`
+	EndDate   string          `json:"end_date"`
+	Overall   PrepTimeStats   `json:"overall"`
+	Stations  []PrepTimeStats `json:"stations"`
+}
+
+// VoidClassification tells a void of an item the kitchen never started from food waste
+type VoidClassification string
+
+const (
+	VoidPreFireCancel VoidClassification = "pre_fire_cancel" // Voided before reaching a station, nothing was cooked
+	VoidPostFireWaste VoidClassification = "post_fire_waste" // Voided after a station started on it
+)
+
+// VoidedItem is one voided order item in the waste report
+type VoidedItem struct {
+	ItemID          uuid.UUID          `db:"item_id" json:"item_id"`
+	OrderID         uuid.UUID          `db:"order_id" json:"order_id"`
+	OrderNumber     string             `db:"order_number" json:"order_number"`
+	MenuItemID      uuid.UUID          `db:"menu_item_id" json:"menu_item_id"`
+	Name            string             `db:"name" json:"name"`
+	StationID       uuid.UUID          `db:"station_id" json:"station_id"`
+	Quantity        float64            `db:"quantity" json:"quantity"`
+	Price           float64            `db:"price" json:"price"`
+	Reason          *string            `db:"reason" json:"reason"`
+	SentToStationAt *time.Time         `db:"sent_to_station_at" json:"sent_to_station_at"`
+	VoidedAt        time.Time          `db:"voided_at" json:"voided_at"`
+	Value           float64            `db:"-" json:"value"`
+	Classification  VoidClassification `db:"-" json:"classification"`
+}
+
+// Classify sets the item's value and whether it was voided before or after reaching a station
+func (v *VoidedItem) Classify() {
+	v.Value = LineTotal(v.Price, v.Quantity)
+	v.Classification = VoidPreFireCancel
+	if v.SentToStationAt != nil && !v.SentToStationAt.After(v.VoidedAt) {
+		v.Classification = VoidPostFireWaste
+	}
+}
+
+// VoidTotals counts voided items and their value
+type VoidTotals struct {
+	ItemCount int     `json:"item_count"`
+	Value     float64 `json:"value"`
+}
+
+// add counts one voided item
+func (t *VoidTotals) add(item VoidedItem) {
+	t.ItemCount++
+	t.Value = AddMoney(t.Value, item.Value)
+}
+
+// OrderWaste is the voids of a single order, split by classification
+type OrderWaste struct {
+	OrderID       uuid.UUID  `json:"order_id"`
+	OrderNumber   string     `json:"order_number"`
+	PreFireCancel VoidTotals `json:"pre_fire_cancel"`
+	PostFireWaste VoidTotals `json:"post_fire_waste"`
+}
+
+// WasteReport splits the items voided in a date range into pre-fire cancels and post-fire waste
+type WasteReport struct {
+	StartDate     string       `json:"start_date"`
+	EndDate       string       `json:"end_date"`
+	PreFireCancel VoidTotals   `json:"pre_fire_cancel"`
+	PostFireWaste VoidTotals   `json:"post_fire_waste"`
+	Orders        []OrderWaste `json:"orders"`
+	Items         []VoidedItem `json:"items"`
+}
+
+// Add classifies a voided item and counts it in the report and its order
+func (r *WasteReport) Add(item VoidedItem) {
+	item.Classify()
+
+	var order *OrderWaste
+	for i := range r.Orders {
+		if r.Orders[i].OrderID == item.OrderID {
+			order = &r.Orders[i]
+			break
+		}
+	}
+	if order == nil {
+		r.Orders = append(r.Orders, OrderWaste{OrderID: item.OrderID, OrderNumber: item.OrderNumber})
+		order = &r.Orders[len(r.Orders)-1]
+	}
+
+	if item.Classification == VoidPostFireWaste {
+		r.PostFireWaste.add(item)
+		order.PostFireWaste.add(item)
+	} else {
+		r.PreFireCancel.add(item)
+		order.PreFireCancel.add(item)
+	}
+
+	r.Items = append(r.Items, item)
+}
@@ -24,12 +24,45 @@ type Station struct {
 	PrinterID *uuid.UUID  `db:"printer_id" json:"printer_id"`
 	DisplayID *uuid.UUID  `db:"display_id" json:"display_id"`
 	IsActive  bool        `db:"is_active" json:"is_active"`
+	CreatedBy *uuid.UUID  `db:"created_by" json:"created_by,omitempty"`
+	UpdatedBy *uuid.UUID  `db:"updated_by" json:"updated_by,omitempty"`
 	CreatedAt time.Time   `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time   `db:"updated_at" json:"updated_at"`
 
+	// Not stored directly in database
+	Printer  *Printer         `db:"-" json:"printer,omitempty"`
+	Display  *Display         `db:"-" json:"display,omitempty"`
+	Printers []StationPrinter `db:"-" json:"printers,omitempty"`
+}
+
+// PrinterRole is what a station uses a printer for
+type PrinterRole string
+
+const (
+	PrinterRoleTicket  PrinterRole = "ticket"
+	PrinterRoleLabel   PrinterRole = "label"
+	PrinterRoleReceipt PrinterRole = "receipt"
+)
+
+// IsValid reports whether the role is a known printer role
+func (r PrinterRole) IsValid() bool {
+	switch r {
+	case PrinterRoleTicket, PrinterRoleLabel, PrinterRoleReceipt:
+		return true
+	}
+	return false
+}
+
+// StationPrinter assigns a printer to a station for one role.
+// The station's printer_id column mirrors its ticket printer for older clients.
+type StationPrinter struct {
+	StationID uuid.UUID   `db:"station_id" json:"station_id"`
+	PrinterID uuid.UUID   `db:"printer_id" json:"printer_id"`
+	Role      PrinterRole `db:"role" json:"role"`
+	CreatedAt time.Time   `db:"created_at" json:"created_at"`
+
 	// Not stored directly in database
 	Printer *Printer `db:"-" json:"printer,omitempty"`
-	Display *Display `db:"-" json:"display,omitempty"`
 }
 
 // RoutingRule represents a rule for routing menu items to stations
@@ -45,6 +78,70 @@ type RoutingRule struct {
 	Station *Station `db:"-" json:"station,omitempty"`
 }
 
+// RoutingBulkRequest is used for routing every item in a category to a station
+type RoutingBulkRequest struct {
+	CategoryID uuid.UUID `json:"category_id" validate:"required"`
+	StationID  uuid.UUID `json:"station_id" validate:"required"`
+	Priority   int       `json:"priority" validate:"omitempty,gte=1"` // Defaults to 1
+	Replace    bool      `json:"replace"`                             // Overwrite the priority of existing rules for the station
+}
+
+// RoutingBulkResult reports the outcome of a bulk routing assignment
+type RoutingBulkResult struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	StationID  uuid.UUID `json:"station_id"`
+	Priority   int       `json:"priority"`
+	Assigned   int       `json:"assigned"`
+	Skipped    int       `json:"skipped"` // Items that already had a rule for the station
+}
+
+// RoutingReassignRequest moves a station's routing to another station before it is decommissioned
+type RoutingReassignRequest struct {
+	TargetStationID uuid.UUID `json:"target_station_id" validate:"required"`
+	MoveOpenItems   bool      `json:"move_open_items"` // Also move pending and in-progress order items
+}
+
+// RoutingReassignResult reports the outcome of a routing reassignment
+type RoutingReassignResult struct {
+	StationID       uuid.UUID `json:"station_id"`
+	TargetStationID uuid.UUID `json:"target_station_id"`
+	Moved           int       `json:"moved"`       // Rules repointed to the target
+	Merged          int       `json:"merged"`      // Rules dropped because the item already routed to the target
+	ItemsMoved      int       `json:"items_moved"` // Open order items moved, when requested
+}
+
+// StationMenuItem is a menu item routed to a station by one of its routing rules. Soft
+// deleted items are listed too, their rules still reference the station.
+type StationMenuItem struct {
+	RuleID     uuid.UUID  `db:"rule_id" json:"rule_id"`
+	MenuItemID uuid.UUID  `db:"menu_item_id" json:"menu_item_id"`
+	Name       string     `db:"name" json:"name"`
+	CategoryID uuid.UUID  `db:"category_id" json:"category_id"`
+	Priority   int        `db:"priority" json:"priority"`
+	Available  bool       `db:"available" json:"available"`
+	DeletedAt  *time.Time `db:"deleted_at" json:"deleted_at,omitempty"` // Set for a deleted item, move its rule before deleting the station
+}
+
+// StationActionType is an action a kitchen display or bump bar can take on a station's items
+type StationActionType string
+
+const (
+	StationActionBump   StationActionType = "bump"   // Advance an item: pending -> in progress -> ready -> completed
+	StationActionRecall StationActionType = "recall" // Bring a ready or completed item back
+	StationActionNext   StationActionType = "next"   // Start the oldest pending item
+)
+
+// StationActionRequest is used for station item actions over HTTP or WebSocket
+type StationActionRequest struct {
+	Action StationActionType `json:"action" validate:"required,oneof=bump recall next"`
+	ItemID *uuid.UUID        `json:"item_id,omitempty"` // Defaults to the oldest (or most recently completed, for recall) item
+}
+
+// StationReorderRequest puts a station's items in the given order, ahead of the rest of its feed
+type StationReorderRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids" validate:"required,min=1"`
+}
+
 // StationRequest is used for station creation/update
 type StationRequest struct {
 	Name      string      `json:"name" validate:"required,min=1,max=100"`
@@ -54,6 +151,17 @@ type StationRequest struct {
 	IsActive  bool        `json:"is_active"`
 }
 
+// StationPrinterAssignment names the printer a station uses for a role
+type StationPrinterAssignment struct {
+	PrinterID uuid.UUID   `json:"printer_id" validate:"required"`
+	Role      PrinterRole `json:"role" validate:"required,oneof=ticket label receipt"`
+}
+
+// StationPrintersRequest replaces every printer assignment of a station
+type StationPrintersRequest struct {
+	Printers []StationPrinterAssignment `json:"printers"`
+}
+
 // RoutingRuleRequest is used for routing rule creation/update
 type RoutingRuleRequest struct {
 	MenuItemID uuid.UUID `json:"menu_item_id" validate:"required"`
@@ -15,6 +15,15 @@ const (
 	RoleKitchen UserRole = "kitchen"
 )
 
+// IsValid reports whether r is a known role
+func (r UserRole) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleManager, RoleCashier, RoleKitchen:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID           uuid.UUID `db:"id" json:"id"`
 	Username     string    `db:"username" json:"username"`
@@ -22,8 +31,10 @@ type User struct {
 	Name         string    `db:"name" json:"name"`
 	Role         UserRole  `db:"role" json:"role"`
 	IsActive     bool      `db:"is_active" json:"is_active"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	// MustChangePassword is set for accounts given a temporary password
+	MustChangePassword bool      `db:"must_change_password" json:"must_change_password"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
 }
 
 // UserRequest is used for user creation/update requests
@@ -34,3 +45,38 @@ type UserRequest struct {
 	Role     UserRole `json:"role" validate:"required,oneof=admin manager cashier kitchen"`
 	IsActive bool     `json:"is_active"`
 }
+
+// MinPasswordLength is the shortest password a user can set, as in UserRequest
+const MinPasswordLength = 6
+
+// PasswordChangeRequest is used by a user to change their own password
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6"`
+}
+
+// UserImportRowResult is the outcome of one row of a user import
+type UserImportRowResult struct {
+	Row      int    `json:"row"` // 1-based line number in the file, the header is row 1
+	Username string `json:"username"`
+	User     *User  `json:"user,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// UserImportResult summarises a user import, failed rows don't stop the others
+type UserImportResult struct {
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+	Rows    []UserImportRowResult `json:"rows"`
+}
+
+// UserStats summarises the orders a staff member took over a date range
+type UserStats struct {
+	UserID       uuid.UUID `db:"-" json:"user_id"`
+	StartDate    string    `db:"-" json:"start_date"`
+	EndDate      string    `db:"-" json:"end_date"`
+	OrderCount   int       `db:"order_count" json:"order_count"`
+	TotalSales   float64   `db:"total_sales" json:"total_sales"`     // Excludes cancelled orders
+	VoidedOrders int       `db:"voided_orders" json:"voided_orders"` // Orders cancelled outright
+	VoidedItems  int       `db:"voided_items" json:"voided_items"`   // Cancelled items on orders that went ahead
+}
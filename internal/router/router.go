@@ -3,8 +3,16 @@ package router
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/api"
+	"github.com/pizza-nz/restaurant-service/internal/api/handler"
 	"github.com/pizza-nz/restaurant-service/internal/db/repository"
 	"github.com/pizza-nz/restaurant-service/internal/middleware"
 	"github.com/pizza-nz/restaurant-service/internal/models"
@@ -12,6 +20,14 @@ import (
 	"github.com/pizza-nz/restaurant-service/internal/websockets"
 )
 
+const (
+	// loginAttemptsPerMinute caps login attempts per client IP and per username
+	loginAttemptsPerMinute = 10
+
+	// failedLoginDelay is added to every failed login to slow guessing and mask timing
+	failedLoginDelay = 300 * time.Millisecond
+)
+
 // Router handles HTTP routing
 type Router struct {
 	mux      *http.ServeMux
@@ -19,16 +35,29 @@ type Router struct {
 	auth     *service.AuthService
 	hub      *websockets.Hub
 	notFound http.Handler
+
+	queryTimeout  time.Duration
+	exportTimeout time.Duration
+
+	loginIPLimiter   *middleware.RateLimiter
+	loginUserLimiter *middleware.RateLimiter
 }
 
-// New creates a new router
-func New(repos *repository.Repositories, auth *service.AuthService, hub *websockets.Hub) *Router {
+// New creates a new router. Every API request, and so every query it runs, is cancelled after
+// queryTimeout, except report exports which get exportTimeout.
+func New(repos *repository.Repositories, auth *service.AuthService, hub *websockets.Hub, queryTimeout, exportTimeout time.Duration) *Router {
 	r := &Router{
 		mux:      http.NewServeMux(),
 		repos:    repos,
 		auth:     auth,
 		hub:      hub,
 		notFound: http.NotFoundHandler(),
+
+		queryTimeout:  queryTimeout,
+		exportTimeout: exportTimeout,
+
+		loginIPLimiter:   middleware.NewRateLimiter(loginAttemptsPerMinute, time.Minute),
+		loginUserLimiter: middleware.NewRateLimiter(loginAttemptsPerMinute, time.Minute),
 	}
 
 	// Set up routes
@@ -47,11 +76,80 @@ func (r *Router) setupRoutes() {
 	// Public routes
 	r.mux.Handle("/api/auth/login", http.HandlerFunc(r.handleLogin))
 	r.mux.Handle("/ws", http.HandlerFunc(r.handleWebSocket))
+	r.mux.Handle("/readyz", http.HandlerFunc(r.handleReadyz))
+
+	// Services and handlers
+	menuService := service.NewMenuService(r.repos)
+	menuHandler := handler.NewMenuHandler(menuService, r.hub)
+	orderService := service.NewOrderService(r.repos)
+	orderHandler := handler.NewOrderHandler(orderService, r.hub)
+	integrationService := service.NewIntegrationService(r.repos, orderService)
+	integrationHandler := handler.NewIntegrationHandler(integrationService, r.hub)
+
+	// Platform webhooks are signed instead of carrying a user token
+	r.mux.Handle("/api/integrations/{provider}/orders", middleware.Logger(
+		middleware.Timeout(r.queryTimeout)(
+			http.HandlerFunc(integrationHandler.HandleInboundOrder),
+		),
+	))
+	printerService := service.NewPrinterService(r.repos)
+	printerHandler := handler.NewPrinterHandler(printerService, r.hub)
+	stationService := service.NewStationService(r.repos, r.hub)
+	stationHandler := handler.NewStationHandler(stationService, r.hub)
+	userService := service.NewUserService(r.repos)
+	userHandler := handler.NewUserHandler(userService)
+	auditService := service.NewAuditService(r.repos)
+	auditHandler := handler.NewAuditHandler(auditService)
+	reportService := service.NewReportService(r.repos)
+	reportHandler := handler.NewReportHandler(reportService)
 
 	// Protected routes
 	apiHandler := http.NewServeMux()
+	apiHandler.Handle("/menu/categories", http.HandlerFunc(menuHandler.HandleCategories))
+	apiHandler.Handle("/menu/categories/{id}", http.HandlerFunc(menuHandler.HandleCategory))
+	apiHandler.Handle("/menu/categories/{id}/merge", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(menuHandler.HandleMergeCategory)))
+	apiHandler.Handle("/menu/items", http.HandlerFunc(menuHandler.HandleMenuItems))
+	apiHandler.Handle("/menu/items/{id}", http.HandlerFunc(menuHandler.HandleMenuItem))
+	apiHandler.Handle("/menu/items/{id}/price-history", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(menuHandler.HandlePriceHistory)))
+	apiHandler.Handle("/menu/items/{id}/availability", http.HandlerFunc(menuHandler.HandleItemAvailability))
+	apiHandler.Handle("/modifiers", http.HandlerFunc(menuHandler.HandleModifiers))
+	apiHandler.Handle("/modifiers/{id}", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(menuHandler.HandleModifier)))
+	apiHandler.Handle("/modifiers/{id}/usage", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(menuHandler.HandleModifierUsage)))
+	apiHandler.Handle("/menu/price-adjust", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(menuHandler.HandlePriceAdjust)))
+	apiHandler.Handle("/menu/items/bulk-delete", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(menuHandler.HandleBulkDeleteItems)))
+	apiHandler.Handle("/orders", http.HandlerFunc(orderHandler.HandleOrders))
+	apiHandler.Handle("/orders/history", http.HandlerFunc(orderHandler.HandleOrderHistory))
+	apiHandler.Handle("/orders/open", http.HandlerFunc(orderHandler.HandleOpenOrders))
+	apiHandler.Handle("/orders/{id}/notes", http.HandlerFunc(orderHandler.HandleOrderNotes))
+	apiHandler.Handle("/orders/{id}/timeline", http.HandlerFunc(orderHandler.HandleOrderTimeline))
+	apiHandler.Handle("/orders/{id}/rush", http.HandlerFunc(orderHandler.HandleOrderRush))
+	apiHandler.Handle("/orders/{id}/hold", http.HandlerFunc(orderHandler.HandleOrderHold))
+	apiHandler.Handle("/orders/{id}/release", http.HandlerFunc(orderHandler.HandleOrderRelease))
+	apiHandler.Handle("/orders/{id}/status", http.HandlerFunc(orderHandler.HandleOrderStatus))
+	apiHandler.Handle("/orders/{id}/items/{itemId}/status", http.HandlerFunc(orderHandler.HandleOrderItemStatus))
+	apiHandler.Handle("/orders/{id}/items/{itemId}/rush", http.HandlerFunc(orderHandler.HandleOrderItemRush))
+	apiHandler.Handle("/orders/{id}/reopen", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(orderHandler.HandleReopenOrder)))
+	apiHandler.Handle("/orders/{id}/recalculate", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(orderHandler.HandleRecalculateTotal)))
+	apiHandler.Handle("/kitchen/active", http.HandlerFunc(orderHandler.HandleKitchenActive))
+	apiHandler.Handle("/stations/items", middleware.RequireRole(models.RoleAdmin, models.RoleManager, models.RoleKitchen)(http.HandlerFunc(stationHandler.HandleStationsItems)))
+	apiHandler.Handle("/stations/{id}/items", http.HandlerFunc(stationHandler.HandleStationItems))
+	apiHandler.Handle("/stations/{id}/menu-items", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(stationHandler.HandleStationMenuItems)))
+	apiHandler.Handle("/stations/{id}/reassign-routing", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(stationHandler.HandleReassignRouting)))
+	apiHandler.Handle("/stations/{id}/reorder", http.HandlerFunc(stationHandler.HandleStationReorder))
+	apiHandler.Handle("/stations/{id}/actions", http.HandlerFunc(stationHandler.HandleStationAction))
+	apiHandler.Handle("/stations/{id}/printers", http.HandlerFunc(stationHandler.HandleStationPrinters))
+	apiHandler.Handle("/routing/bulk", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(stationHandler.HandleRoutingBulk)))
+	apiHandler.Handle("/printers", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(printerHandler.HandlePrinters)))
+	apiHandler.Handle("/printers/{id}", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(printerHandler.HandlePrinter)))
+	apiHandler.Handle("/printers/default", http.HandlerFunc(printerHandler.HandleDefaultPrinter))
+	apiHandler.Handle("/printers/{id}/set-default", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(printerHandler.HandleSetDefaultPrinter)))
+	apiHandler.Handle("/auth/password", http.HandlerFunc(r.handleChangePassword))
+	apiHandler.Handle("/users/import", middleware.RequireRole(models.RoleAdmin)(http.HandlerFunc(userHandler.HandleImportUsers)))
+	apiHandler.Handle("/users/{id}/stats", http.HandlerFunc(userHandler.HandleUserStats))
+	apiHandler.Handle("/reports/prep-times", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(reportHandler.HandlePrepTimes)))
+	apiHandler.Handle("/reports/waste", middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(reportHandler.HandleWaste)))
+	apiHandler.Handle("/audit", middleware.RequireRole(models.RoleAdmin)(http.HandlerFunc(auditHandler.HandleAuditLogs)))
 	// apiHandler.Handle("/users", r.requireRole(models.RoleAdmin, http.HandlerFunc(r.handleUsers)))
-	// apiHandler.Handle("/menu/categories", http.HandlerFunc(r.handleMenuCategories))
 	// apiHandler.Handle("/menu/items", http.HandlerFunc(r.handleMenuItems))
 	// apiHandler.Handle("/orders", http.HandlerFunc(r.handleOrders))
 	// apiHandler.Handle("/stations", http.HandlerFunc(r.handleStations))
@@ -59,12 +157,23 @@ func (r *Router) setupRoutes() {
 
 	// Apply middleware to protected routes
 	apiChain := middleware.Logger(
-		middleware.Auth(r.auth)(
-			apiHandler,
+		middleware.Timeout(r.queryTimeout)(
+			middleware.Auth(r.auth)(
+				apiHandler,
+			),
 		),
 	)
 
 	r.mux.Handle("/api/", http.StripPrefix("/api", apiChain))
+
+	// Exports read a whole business day, so they run under their own timeout
+	r.mux.Handle("/api/reports/orders.csv", middleware.Logger(
+		middleware.Timeout(r.exportTimeout)(
+			middleware.Auth(r.auth)(
+				middleware.RequireRole(models.RoleAdmin, models.RoleManager)(http.HandlerFunc(reportHandler.HandleOrdersCSV)),
+			),
+		),
+	))
 }
 
 // requireRole creates a middleware that checks if the user has the required role
@@ -72,7 +181,7 @@ func (r *Router) requireRole(role models.UserRole, next http.Handler) http.Handl
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		userRole, ok := middleware.GetUserRole(req.Context())
 		if !ok || userRole != role {
-			http.Error(w, "Forbidden", http.StatusForbidden)
+			api.Forbidden(w, "Forbidden")
 			return
 		}
 		next.ServeHTTP(w, req)
@@ -82,7 +191,12 @@ func (r *Router) requireRole(role models.UserRole, next http.Handler) http.Handl
 // handleLogin handles user login
 func (r *Router) handleLogin(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		api.MethodNotAllowed(w)
+		return
+	}
+
+	if !r.loginIPLimiter.Allow(middleware.ClientIP(req)) {
+		r.tooManyLoginAttempts(w)
 		return
 	}
 
@@ -92,15 +206,33 @@ func (r *Router) handleLogin(w http.ResponseWriter, req *http.Request) {
 	}
 
 	// Decode the request body
-	if err := json.NewDecoder(req.Body).Decode(&loginReq); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !api.DecodeJSON(w, req, &loginReq) {
+		return
+	}
+
+	if loginReq.Username == "" || loginReq.Password == "" {
+		api.BadRequest(w, "username and password are required")
+		return
+	}
+
+	if !r.loginUserLimiter.Allow(strings.ToLower(loginReq.Username)) {
+		r.tooManyLoginAttempts(w)
 		return
 	}
 
 	// Attempt to login
 	token, user, err := r.auth.Login(req.Context(), loginReq.Username, loginReq.Password)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			time.Sleep(failedLoginDelay)
+			api.Unauthorized(w, "Invalid username or password")
+		case errors.Is(err, service.ErrAccountInactive):
+			api.Forbidden(w, "Account is inactive")
+		default:
+			log.Printf("Failed to log in: %v", err)
+			api.InternalServerError(w, "Failed to log in")
+		}
 		return
 	}
 
@@ -117,18 +249,86 @@ func (r *Router) handleLogin(w http.ResponseWriter, req *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// handleChangePassword handles POST /auth/password, a user changing their own password
+func (r *Router) handleChangePassword(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		api.MethodNotAllowed(w)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(req.Context())
+	if !ok {
+		api.Unauthorized(w, "Authentication required")
+		return
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		api.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var changeReq models.PasswordChangeRequest
+	if !api.DecodeJSON(w, req, &changeReq) {
+		return
+	}
+
+	if changeReq.CurrentPassword == "" || changeReq.NewPassword == "" {
+		api.BadRequest(w, "current_password and new_password are required")
+		return
+	}
+
+	err = r.auth.ChangePassword(req.Context(), id, changeReq.CurrentPassword, changeReq.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWeakPassword):
+			api.BadRequest(w, err.Error())
+		case errors.Is(err, service.ErrIncorrectPassword):
+			time.Sleep(failedLoginDelay)
+			api.Unauthorized(w, "Current password is incorrect")
+		default:
+			log.Printf("Failed to change password: %v", err)
+			api.InternalServerError(w, "Failed to change password")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tooManyLoginAttempts rejects a rate-limited login request
+func (r *Router) tooManyLoginAttempts(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(r.loginIPLimiter.Window().Seconds())))
+	api.TooManyRequests(w, "Too many login attempts, try again later")
+}
+
+// handleReadyz reports whether the server can take traffic, including WebSocket connections
+func (r *Router) handleReadyz(w http.ResponseWriter, req *http.Request) {
+	if !r.hub.Ready() {
+		api.ServiceUnavailable(w, "WebSocket hub is not running")
+		return
+	}
+
+	api.JSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
 // handleWebSocket handles WebSocket connections
 func (r *Router) handleWebSocket(w http.ResponseWriter, req *http.Request) {
+	// Registrations would block until the hub is running
+	if !r.hub.Ready() {
+		api.ServiceUnavailable(w, "WebSocket hub is not running")
+		return
+	}
+
 	// Get user ID and client type from the request
 	userID := req.URL.Query().Get("user_id")
 	if userID == "" {
-		http.Error(w, "user_id is required", http.StatusBadRequest)
+		api.BadRequest(w, "user_id is required")
 		return
 	}
 
 	clientTypeStr := req.URL.Query().Get("client_type")
 	if clientTypeStr == "" {
-		http.Error(w, "client_type is required", http.StatusBadRequest)
+		api.BadRequest(w, "client_type is required")
 		return
 	}
 
@@ -140,12 +340,12 @@ func (r *Router) handleWebSocket(w http.ResponseWriter, req *http.Request) {
 		websockets.ClientTypeDisplay, websockets.ClientTypePrinter:
 		// Valid client type
 	default:
-		http.Error(w, "invalid client_type", http.StatusBadRequest)
+		api.BadRequest(w, "invalid client_type")
 		return
 	}
 
 	// Upgrade the HTTP connection to a WebSocket connection
-	conn, err := websockets.Upgrader.Upgrade(w, req, nil)
+	conn, err := websockets.Upgrade(w, req, nil)
 	if err != nil {
 		// If upgrading fails, the upgrader has already written the error to the response
 		return
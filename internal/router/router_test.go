@@ -0,0 +1,37 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoginRateLimit(t *testing.T) {
+	r := New(nil, nil, nil, time.Second, time.Second)
+
+	login := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{}`))
+		req.RemoteAddr = "192.0.2.10:51234"
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Attempts within the limit reach the handler, which rejects the empty credentials
+	for i := 0; i < loginAttemptsPerMinute; i++ {
+		if rec := login(); rec.Code != http.StatusBadRequest {
+			t.Fatalf("attempt %d: status = %d, want %d", i, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	rec := login()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got, want := rec.Header().Get("Retry-After"), strconv.Itoa(int(time.Minute.Seconds())); got != want {
+		t.Errorf("Retry-After = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+// Page sizes shared by the paginated listings
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ErrInvalidPagination is returned for a negative offset or a limit outside 1-200
+var ErrInvalidPagination = errors.New("limit must be between 1 and 200 and offset must not be negative")
+
+// AuditService handles audit log queries
+type AuditService struct {
+	repos *repository.Repositories
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repos *repository.Repositories) *AuditService {
+	return &AuditService{
+		repos: repos,
+	}
+}
+
+// ListAuditLogs returns one page of audit log entries matching filter, newest first.
+// A zero limit uses the default page size.
+func (s *AuditService) ListAuditLogs(ctx context.Context, filter models.AuditLogFilter) (*models.AuditLogPage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize || filter.Offset < 0 {
+		return nil, ErrInvalidPagination
+	}
+	if filter.Start != nil && filter.End != nil && filter.End.Before(*filter.Start) {
+		return nil, ErrInvalidDateRange
+	}
+
+	entries, total, err := s.repos.Audit.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuditLogPage{
+		Entries: entries,
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}, nil
+}
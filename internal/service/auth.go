@@ -13,30 +13,73 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	// ErrInvalidCredentials is returned for an unknown username or a wrong password alike
+	ErrInvalidCredentials = errors.New("invalid username or password")
+
+	// ErrAccountInactive is returned when a deactivated user logs in with the right password
+	ErrAccountInactive = errors.New("account is inactive")
+
+	// ErrIncorrectPassword is returned when a password change gives the wrong current password
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+
+	// ErrWeakPassword is returned for a new password shorter than models.MinPasswordLength
+	ErrWeakPassword = fmt.Errorf("password must be at least %d characters", models.MinPasswordLength)
+)
+
+// dummyPasswordHash is compared against when a username doesn't exist
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("restaurant-service-dummy"), bcrypt.DefaultCost)
+
 // JWTConfig holds configuration for JWT token generation
 type JWTConfig struct {
-	Secret    string
-	ExpiresIn int // hours
+	Secret          string
+	ExpiresIn       int // hours
+	Algorithm       string
+	PreviousSecrets []string // accepted when validating, never used for signing
 }
 
 // AuthService handles authentication and authorization
 type AuthService struct {
-	repos     *repository.Repositories
-	jwtConfig JWTConfig
+	repos         *repository.Repositories
+	jwtConfig     JWTConfig
+	signingMethod *jwt.SigningMethodHMAC
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(repos *repository.Repositories, jwtConfig JWTConfig) *AuthService {
-	return &AuthService{
-		repos:     repos,
-		jwtConfig: jwtConfig,
+func NewAuthService(repos *repository.Repositories, jwtConfig JWTConfig) (*AuthService, error) {
+	if jwtConfig.Algorithm == "" {
+		jwtConfig.Algorithm = jwt.SigningMethodHS256.Alg()
 	}
+
+	var signingMethod *jwt.SigningMethodHMAC
+	switch jwtConfig.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		signingMethod = jwt.SigningMethodHS256
+	case jwt.SigningMethodHS384.Alg():
+		signingMethod = jwt.SigningMethodHS384
+	case jwt.SigningMethodHS512.Alg():
+		signingMethod = jwt.SigningMethodHS512
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", jwtConfig.Algorithm)
+	}
+
+	if jwtConfig.Secret == "" {
+		return nil, errors.New("JWT secret is required")
+	}
+
+	return &AuthService{
+		repos:         repos,
+		jwtConfig:     jwtConfig,
+		signingMethod: signingMethod,
+	}, nil
 }
 
 // Claims represents JWT claims
 type Claims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	// MustChangePassword limits the token to changing the password, the user logs in again after
+	MustChangePassword bool `json:"must_change_password,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -45,22 +88,27 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (str
 	// Get user by username
 	user, err := s.repos.User.GetByUsername(ctx, username)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid credentials")
-	}
-
-	// Check if user is active
-	if !user.IsActive {
-		return "", nil, fmt.Errorf("user account is inactive")
+		// Compare anyway so unknown usernames take as long as wrong passwords
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", nil, ErrInvalidCredentials
+		}
+		return "", nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Check password
+	// Check password before anything else, so an inactive account isn't revealed to a guess
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid credentials")
+		return "", nil, ErrInvalidCredentials
+	}
+
+	// Check if user is active
+	if !user.IsActive {
+		return "", nil, ErrAccountInactive
 	}
 
 	// Generate JWT token
-	token, err := s.generateToken(user.ID, user.Role)
+	token, err := s.generateToken(user.ID, user.Role, user.MustChangePassword)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -69,12 +117,13 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (str
 }
 
 // generateToken generates a JWT token for a user
-func (s *AuthService) generateToken(userID uuid.UUID, role models.UserRole) (string, error) {
+func (s *AuthService) generateToken(userID uuid.UUID, role models.UserRole, mustChangePassword bool) (string, error) {
 	expirationTime := time.Now().Add(time.Duration(s.jwtConfig.ExpiresIn) * time.Hour)
 
 	claims := &Claims{
-		UserID: userID.String(),
-		Role:   string(role),
+		UserID:             userID.String(),
+		Role:               string(role),
+		MustChangePassword: mustChangePassword,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -82,7 +131,7 @@ func (s *AuthService) generateToken(userID uuid.UUID, role models.UserRole) (str
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(s.signingMethod, claims)
 	tokenString, err := token.SignedString([]byte(s.jwtConfig.Secret))
 	if err != nil {
 		return "", err
@@ -92,25 +141,36 @@ func (s *AuthService) generateToken(userID uuid.UUID, role models.UserRole) (str
 }
 
 // ValidateToken validates a JWT token and returns the claims
+// Tokens signed with the current secret or any previous secret are accepted
 func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
-	claims := &Claims{}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{s.signingMethod.Alg()}))
+
+	secrets := append([]string{s.jwtConfig.Secret}, s.jwtConfig.PreviousSecrets...)
+
+	var lastErr error
+	for _, secret := range secrets {
+		claims := &Claims{}
+
+		token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		})
+		if err != nil {
+			lastErr = err
+			// Only a signature mismatch is worth retrying with an older key
+			if errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+				continue
+			}
+			return nil, err
 		}
-		return []byte(s.jwtConfig.Secret), nil
-	})
 
-	if err != nil {
-		return nil, err
-	}
+		if !token.Valid {
+			return nil, errors.New("invalid token")
+		}
 
-	if !token.Valid {
-		return nil, errors.New("invalid token")
+		return claims, nil
 	}
 
-	return claims, nil
+	return nil, lastErr
 }
 
 // GetUserFromToken gets the user associated with a token
@@ -161,6 +221,10 @@ func (s *AuthService) RegisterUser(ctx context.Context, req models.UserRequest)
 
 // ChangePassword changes a user's password
 func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error {
+	if len(newPassword) < models.MinPasswordLength {
+		return ErrWeakPassword
+	}
+
 	// Get the user
 	user, err := s.repos.User.GetByID(ctx, userID)
 	if err != nil {
@@ -170,7 +234,7 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, curr
 	// Verify current password
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword))
 	if err != nil {
-		return fmt.Errorf("current password is incorrect")
+		return ErrIncorrectPassword
 	}
 
 	// Hash the new password
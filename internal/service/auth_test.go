@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestValidateTokenSecretRotation(t *testing.T) {
+	sign := func(t *testing.T, method jwt.SigningMethod, secret string) string {
+		t.Helper()
+		claims := &Claims{
+			UserID: "8d4f6a2e-3c1b-4e7a-9f0d-2b5c8e1a7d3f",
+			Role:   "kitchen",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+		token, err := jwt.NewWithClaims(method, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name   string
+		config JWTConfig
+		method jwt.SigningMethod
+		secret string
+		valid  bool
+	}{
+		{"current secret", JWTConfig{Secret: "new-secret"}, jwt.SigningMethodHS256, "new-secret", true},
+		{"previous secret", JWTConfig{Secret: "new-secret", PreviousSecrets: []string{"old-secret"}}, jwt.SigningMethodHS256, "old-secret", true},
+		{"previous secret removed", JWTConfig{Secret: "new-secret"}, jwt.SigningMethodHS256, "old-secret", false},
+		{"unknown secret", JWTConfig{Secret: "new-secret", PreviousSecrets: []string{"old-secret"}}, jwt.SigningMethodHS256, "other-secret", false},
+		{"other algorithm", JWTConfig{Secret: "new-secret"}, jwt.SigningMethodHS512, "new-secret", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService, err := NewAuthService(nil, tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := authService.ValidateToken(sign(t, tt.method, tt.secret))
+			if got := err == nil; got != tt.valid {
+				t.Fatalf("ValidateToken() err = %v, want valid %v", err, tt.valid)
+			}
+			if err == nil && claims.Role != "kitchen" {
+				t.Errorf("role = %q, want kitchen", claims.Role)
+			}
+		})
+	}
+}
+
+func TestNewAuthService(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  JWTConfig
+		wantErr bool
+	}{
+		{"default algorithm", JWTConfig{Secret: "secret"}, false},
+		{"HS512", JWTConfig{Secret: "secret", Algorithm: "HS512"}, false},
+		{"unsupported algorithm", JWTConfig{Secret: "secret", Algorithm: "RS256"}, true},
+		{"none algorithm", JWTConfig{Secret: "secret", Algorithm: "none"}, true},
+		{"no secret", JWTConfig{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthService(nil, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewAuthService() err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
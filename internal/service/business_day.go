@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidBusinessDayCutoff is returned for a cutoff that isn't an HH:MM time before noon
+var ErrInvalidBusinessDayCutoff = errors.New("invalid business day cutoff")
+
+// businessDayCutoff is how long after midnight a business day starts, see SetBusinessDayCutoff
+var businessDayCutoff time.Duration
+
+// SetBusinessDayCutoff sets when business days start as an HH:MM time, call it before serving requests.
+// Orders placed before the cutoff count toward the previous day in reports and order numbers.
+func SetBusinessDayCutoff(cutoff string) error {
+	t, err := time.Parse("15:04", cutoff)
+	if err != nil {
+		return fmt.Errorf("%w: %q, expected HH:MM", ErrInvalidBusinessDayCutoff, cutoff)
+	}
+	if t.Hour() >= 12 {
+		return fmt.Errorf("%w: %q must be before 12:00", ErrInvalidBusinessDayCutoff, cutoff)
+	}
+
+	businessDayCutoff = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return nil
+}
+
+// BusinessDayStart returns when the business day for date begins, in date's location
+func BusinessDayStart(date time.Time) time.Time {
+	hours := int(businessDayCutoff / time.Hour)
+	minutes := int(businessDayCutoff % time.Hour / time.Minute)
+	return time.Date(date.Year(), date.Month(), date.Day(), hours, minutes, 0, 0, date.Location())
+}
+
+// BusinessDate returns midnight of the business day t falls in, in t's location
+func BusinessDate(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if t.Before(BusinessDayStart(day)) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
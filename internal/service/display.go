@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+// DisplayService handles display liveness tracking
+type DisplayService struct {
+	repos *repository.Repositories
+	hub   websockets.Broadcaster
+}
+
+// NewDisplayService creates a new display service
+func NewDisplayService(repos *repository.Repositories, hub websockets.Broadcaster) *DisplayService {
+	if hub == nil {
+		hub = websockets.Discard
+	}
+
+	return &DisplayService{
+		repos: repos,
+		hub:   hub,
+	}
+}
+
+// RecordHeartbeat stores a heartbeat reported over a display's WebSocket connection
+func (s *DisplayService) RecordHeartbeat(displayID string) {
+	id, err := uuid.Parse(displayID)
+	if err != nil {
+		log.Printf("Ignoring heartbeat from invalid display ID %q", displayID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.repos.Printer.TouchDisplay(ctx, id); err != nil {
+		log.Printf("Failed to record heartbeat for display %s: %v", id, err)
+	}
+}
+
+// MonitorHeartbeats checks display liveness every interval until ctx is cancelled,
+// broadcasting display.offline when an active display stops reporting
+func (s *DisplayService) MonitorHeartbeats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	online := make(map[uuid.UUID]bool)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkDisplays(ctx, online)
+		}
+	}
+}
+
+// checkDisplays compares current liveness against the last check and reports displays that went offline
+func (s *DisplayService) checkDisplays(ctx context.Context, online map[uuid.UUID]bool) {
+	displays, err := s.repos.Printer.ListDisplays(ctx)
+	if err != nil {
+		log.Printf("Failed to check display heartbeats: %v", err)
+		return
+	}
+
+	for _, display := range displays {
+		wasOnline := online[display.ID]
+		online[display.ID] = display.Online
+
+		if !display.IsActive || !wasOnline || display.Online {
+			continue
+		}
+
+		data := map[string]interface{}{
+			"display_id":   display.ID,
+			"name":         display.Name,
+			"last_seen_at": display.LastSeenAt,
+		}
+		if err := s.hub.BroadcastMessage(websockets.TypeDisplayOffline, data); err != nil {
+			log.Printf("Failed to broadcast display offline: %v", err)
+		}
+	}
+}
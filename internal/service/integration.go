@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+var (
+	// ErrUnknownProvider is returned for a platform with no adapter or no configuration
+	ErrUnknownProvider = errors.New("unknown integration provider")
+
+	// ErrInvalidSignature is returned when a webhook's signature doesn't match its timestamp
+	// and body, or the timestamp is too far from now
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrInvalidInboundOrder is returned when a webhook payload can't be read as an order
+	ErrInvalidInboundOrder = errors.New("invalid inbound order")
+)
+
+// OrderAdapter turns one platform's order webhook payload into an InboundOrder
+type OrderAdapter interface {
+	ParseOrder(body []byte) (*models.InboundOrder, error)
+}
+
+// signatureTolerance is how far a webhook's signed timestamp may be from now, so a captured
+// delivery can't be replayed later
+const signatureTolerance = 5 * time.Minute
+
+// orderAdapters holds the adapter for each platform, by provider name
+var orderAdapters = map[string]OrderAdapter{
+	"generic": genericOrderAdapter{},
+}
+
+// RegisterOrderAdapter adds or replaces the adapter for a provider, call it before SetIntegrationProviders
+func RegisterOrderAdapter(provider string, adapter OrderAdapter) {
+	orderAdapters[provider] = adapter
+}
+
+// IntegrationProvider is the configuration of one platform that sends orders
+type IntegrationProvider struct {
+	Secret   string // Key the platform signs webhook timestamps and bodies with, HMAC-SHA256
+	Username string // User the platform's orders are recorded against
+}
+
+// integrationProviders holds the configured platforms, see SetIntegrationProviders
+var integrationProviders = map[string]IntegrationProvider{}
+
+// SetIntegrationProviders validates and sets the platforms allowed to send orders, call it
+// before serving requests. Every provider needs an adapter, a secret and a username.
+func SetIntegrationProviders(providers map[string]IntegrationProvider) error {
+	for name, p := range providers {
+		if _, ok := orderAdapters[name]; !ok {
+			return fmt.Errorf("%w: no adapter for %q", ErrUnknownProvider, name)
+		}
+		if p.Secret == "" || p.Username == "" {
+			return fmt.Errorf("integration provider %q needs a secret and a username", name)
+		}
+	}
+
+	integrationProviders = providers
+	return nil
+}
+
+// IntegrationService receives orders from third-party platforms
+type IntegrationService struct {
+	repos  *repository.Repositories
+	orders *OrderService
+}
+
+// NewIntegrationService creates a new integration service
+func NewIntegrationService(repos *repository.Repositories, orders *OrderService) *IntegrationService {
+	return &IntegrationService{
+		repos:  repos,
+		orders: orders,
+	}
+}
+
+// ReceiveOrder checks a platform's webhook signature and timestamp, maps its payload to an order
+// and creates it. An order the platform already sent is returned as is, with created false.
+func (s *IntegrationService) ReceiveOrder(ctx context.Context, provider string, body []byte, timestamp, signature string) (order *models.Order, created bool, err error) {
+	config, ok := integrationProviders[provider]
+	adapter := orderAdapters[provider]
+	if !ok || adapter == nil {
+		return nil, false, fmt.Errorf("%w: %q", ErrUnknownProvider, provider)
+	}
+
+	if !validSignature(config.Secret, timestamp, body, signature, time.Now()) {
+		return nil, false, ErrInvalidSignature
+	}
+
+	inbound, err := adapter.ParseOrder(body)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidInboundOrder, err)
+	}
+	if inbound.ExternalID == "" || len(inbound.ExternalID) > 100 {
+		return nil, false, fmt.Errorf("%w: order ID must be 1 to 100 characters", ErrInvalidInboundOrder)
+	}
+	if len(inbound.Items) == 0 {
+		return nil, false, fmt.Errorf("%w: order has no items", ErrInvalidInboundOrder)
+	}
+
+	// Platforms retry deliveries, so a repeat returns the order already created
+	existing, err := s.repos.Order.GetByExternalID(ctx, provider, inbound.ExternalID)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, err
+	}
+
+	user, err := s.repos.User.GetByUsername(ctx, config.Username)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get integration user %q: %w", config.Username, err)
+	}
+
+	req, err := s.orderRequest(ctx, inbound)
+	if err != nil {
+		return nil, false, err
+	}
+
+	order, err = s.orders.CreateOnlineOrder(ctx, user.ID, *req, provider, inbound.ExternalID)
+	if errors.Is(err, repository.ErrDuplicateExternalOrder) {
+		existing, err := s.repos.Order.GetByExternalID(ctx, provider, inbound.ExternalID)
+		return existing, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return order, true, nil
+}
+
+// orderRequest resolves an inbound order's PLUs to menu items, reporting every unknown PLU together
+func (s *IntegrationService) orderRequest(ctx context.Context, inbound *models.InboundOrder) (*models.OrderRequest, error) {
+	req := &models.OrderRequest{
+		Notes:         inbound.Notes,
+		CustomerName:  inbound.CustomerName,
+		CustomerPhone: inbound.CustomerPhone,
+		Items:         make([]models.OrderItemRequest, 0, len(inbound.Items)),
+	}
+
+	var problems []models.OrderItemProblem
+	for i, line := range inbound.Items {
+		item, err := s.repos.Menu.GetItemByPLU(ctx, line.PLU)
+		if errors.Is(err, repository.ErrNotFound) {
+			problems = append(problems, models.OrderItemProblem{Index: i, Message: fmt.Sprintf("unknown PLU %q", line.PLU)})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		req.Items = append(req.Items, models.OrderItemRequest{
+			MenuItemID:          item.ID,
+			Quantity:            line.Quantity,
+			SpecialInstructions: line.SpecialInstructions,
+		})
+	}
+	if len(problems) > 0 {
+		return nil, &OrderItemsError{Problems: problems}
+	}
+
+	return req, nil
+}
+
+// validSignature reports whether signature is the hex HMAC-SHA256 of "<timestamp>.<body>" under
+// secret, with or without a "sha256=" prefix, and timestamp is Unix seconds within
+// signatureTolerance of now
+func validSignature(secret, timestamp string, body []byte, signature string, now time.Time) bool {
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return false
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// genericOrderAdapter reads the service's own webhook format, for platforms or middleware
+// that can be configured to send it:
+//
+//	{"id": "A123", "customer": {"name": "Sam", "phone": "021 555 0100"}, "notes": "Leave at door",
+//	 "items": [{"plu": "PZ-MARG", "quantity": 2, "instructions": "Extra crispy"}]}
+type genericOrderAdapter struct{}
+
+// ParseOrder implements OrderAdapter
+func (genericOrderAdapter) ParseOrder(body []byte) (*models.InboundOrder, error) {
+	var payload struct {
+		ID       string `json:"id"`
+		Customer struct {
+			Name  *string `json:"name"`
+			Phone *string `json:"phone"`
+		} `json:"customer"`
+		Notes *string `json:"notes"`
+		Items []struct {
+			PLU          string  `json:"plu"`
+			Quantity     float64 `json:"quantity"`
+			Instructions *string `json:"instructions"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	order := &models.InboundOrder{
+		ExternalID:    payload.ID,
+		CustomerName:  payload.Customer.Name,
+		CustomerPhone: payload.Customer.Phone,
+		Notes:         payload.Notes,
+		Items:         make([]models.InboundOrderItem, 0, len(payload.Items)),
+	}
+	for _, item := range payload.Items {
+		order.Items = append(order.Items, models.InboundOrderItem{
+			PLU:                 item.PLU,
+			Quantity:            item.Quantity,
+			SpecialInstructions: item.Instructions,
+		})
+	}
+
+	return order, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestValidSignature(t *testing.T) {
+	const secret = "shared-webhook-secret"
+	body := []byte(`{"id":"A123"}`)
+	now := time.Unix(1_700_000_000, 0)
+	sign := func(timestamp string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "." + string(body)))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	at := func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+
+	tests := []struct {
+		name      string
+		timestamp string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"current", at(0), body, sign(at(0), body), true},
+		{"sha256 prefix", at(0), body, "sha256=" + sign(at(0), body), true},
+		{"within tolerance", at(-4 * time.Minute), body, sign(at(-4*time.Minute), body), true},
+		{"stale", at(-6 * time.Minute), body, sign(at(-6*time.Minute), body), false},
+		{"from the future", at(6 * time.Minute), body, sign(at(6*time.Minute), body), false},
+		{"timestamp swapped", at(-time.Minute), body, sign(at(0), body), false},
+		{"body changed", at(0), []byte(`{"id":"A124"}`), sign(at(0), body), false},
+		{"missing timestamp", "", body, sign("", body), false},
+		{"missing signature", at(0), body, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validSignature(secret, tt.timestamp, tt.body, tt.signature, now); got != tt.want {
+				t.Errorf("validSignature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
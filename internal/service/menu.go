@@ -3,13 +3,52 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/pizza-nz/restaurant-service/internal/db/repository"
 	"github.com/pizza-nz/restaurant-service/internal/models"
 )
 
+var (
+	// ErrStationInactive is returned when assigning menu items to an inactive station
+	ErrStationInactive = errors.New("station is inactive")
+
+	// ErrInvalidMenuItem is returned when a menu item request fails validation
+	ErrInvalidMenuItem = errors.New("invalid menu item")
+
+	// ErrInvalidCategory is returned when a menu category request fails validation
+	ErrInvalidCategory = errors.New("invalid menu category")
+
+	// ErrInvalidPriceAdjustment is returned when a bulk price adjustment request fails validation
+	ErrInvalidPriceAdjustment = errors.New("invalid price adjustment")
+
+	// ErrInvalidModifier is returned when a modifier's selection limits are inconsistent
+	ErrInvalidModifier = errors.New("invalid modifier")
+
+	// ErrTooManyOptions is returned when a modifier has more options than SetMaxModifierOptions allows
+	ErrTooManyOptions = errors.New("modifier has too many options")
+)
+
+// DefaultMaxModifierOptions is how many options a modifier can have unless configured otherwise
+const DefaultMaxModifierOptions = 50
+
+// maxModifierOptions caps the options per modifier, see SetMaxModifierOptions
+var maxModifierOptions = DefaultMaxModifierOptions
+
+// SetMaxModifierOptions sets how many options a modifier can have, call it before serving requests
+func SetMaxModifierOptions(n int) error {
+	if n < 1 {
+		return fmt.Errorf("max modifier options must be at least 1, got %d", n)
+	}
+
+	maxModifierOptions = n
+	return nil
+}
+
 // MenuService handles menu-related business logic
 type MenuService struct {
 	repos *repository.Repositories
@@ -34,10 +73,15 @@ func (s *MenuService) GetCategory(ctx context.Context, id uuid.UUID) (*models.Me
 
 // CreateCategory creates a new menu category
 func (s *MenuService) CreateCategory(ctx context.Context, req models.MenuCategoryRequest) (*models.MenuCategory, error) {
+	if err := s.validateCategory(ctx, req); err != nil {
+		return nil, err
+	}
+
 	category := models.MenuCategory{
-		Name:         req.Name,
-		DisplayOrder: req.DisplayOrder,
-		ColorCode:    req.ColorCode,
+		Name:             strings.TrimSpace(req.Name),
+		DisplayOrder:     req.DisplayOrder,
+		ColorCode:        req.ColorCode,
+		DefaultStationID: req.DefaultStationID,
 	}
 
 	return s.repos.Menu.CreateCategory(ctx, category)
@@ -51,22 +95,75 @@ func (s *MenuService) UpdateCategory(ctx context.Context, id uuid.UUID, req mode
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
 
+	if err := s.validateCategory(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// Update the fields
-	existingCategory.Name = req.Name
+	existingCategory.Name = strings.TrimSpace(req.Name)
 	existingCategory.DisplayOrder = req.DisplayOrder
 	existingCategory.ColorCode = req.ColorCode
+	existingCategory.DefaultStationID = req.DefaultStationID
 
 	return s.repos.Menu.UpdateCategory(ctx, *existingCategory)
 }
 
+// validateCategory checks a category request, including that its default station exists
+func (s *MenuService) validateCategory(ctx context.Context, req models.MenuCategoryRequest) error {
+	if name := strings.TrimSpace(req.Name); name == "" || len(name) > 50 {
+		return fmt.Errorf("%w: name must be 1 to 50 characters", ErrInvalidCategory)
+	}
+	if req.ColorCode != nil && len(*req.ColorCode) != 7 {
+		return fmt.Errorf("%w: color_code must be 7 characters, like #ff8800", ErrInvalidCategory)
+	}
+
+	if req.DefaultStationID != nil {
+		_, err := s.repos.Station.GetByID(ctx, *req.DefaultStationID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: default station %s not found", ErrInvalidCategory, *req.DefaultStationID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // DeleteCategory deletes a menu category
 func (s *MenuService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
 	return s.repos.Menu.DeleteCategory(ctx, id)
 }
 
-// GetItems retrieves menu items, optionally filtered by category
-func (s *MenuService) GetItems(ctx context.Context, categoryID *uuid.UUID) ([]models.MenuItem, error) {
-	return s.repos.Menu.ListItems(ctx, categoryID)
+// MergeCategories folds the source category into the target category
+func (s *MenuService) MergeCategories(ctx context.Context, sourceID, targetID uuid.UUID) (*models.MenuCategory, error) {
+	if sourceID == targetID {
+		return nil, errors.New("cannot merge a category into itself")
+	}
+
+	// Verify both categories exist
+	_, err := s.repos.Menu.GetCategoryByID(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source category ID: %w", err)
+	}
+
+	_, err = s.repos.Menu.GetCategoryByID(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target category ID: %w", err)
+	}
+
+	_, err = s.repos.Menu.MergeCategories(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge categories: %w", err)
+	}
+
+	return s.repos.Menu.GetCategoryByID(ctx, targetID)
+}
+
+// GetItems retrieves menu items matching filter
+func (s *MenuService) GetItems(ctx context.Context, filter models.MenuItemFilter) ([]models.MenuItem, error) {
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+	return s.repos.Menu.ListItems(ctx, filter)
 }
 
 // GetItem retrieves a menu item by ID
@@ -74,40 +171,86 @@ func (s *MenuService) GetItem(ctx context.Context, id uuid.UUID) (*models.MenuIt
 	return s.repos.Menu.GetItemByID(ctx, id)
 }
 
-// CreateItem creates a new menu item
-func (s *MenuService) CreateItem(ctx context.Context, req models.MenuItemRequest) (*models.MenuItem, error) {
+// CreateItem creates a new menu item on behalf of userID
+func (s *MenuService) CreateItem(ctx context.Context, userID uuid.UUID, req models.MenuItemRequest) (*models.MenuItem, error) {
 	// Verify the category exists
-	_, err := s.repos.Menu.GetCategoryByID(ctx, req.CategoryID)
+	category, err := s.repos.Menu.GetCategoryByID(ctx, req.CategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid category ID: %w", err)
+		return nil, fmt.Errorf("%w: invalid category ID: %w", ErrInvalidMenuItem, err)
 	}
 
-	// Verify the station exists
-	stationID, err := uuid.Parse(req.StationID)
+	stationID, err := itemStationID(req.StationID, category)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the station exists and can receive items
+	station, err := s.repos.Station.GetByID(ctx, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid station ID: %w", ErrInvalidMenuItem, err)
+	}
+
+	if !station.IsActive {
+		return nil, ErrStationInactive
+	}
+
+	if err := validateRequiredModifiers(req); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.normalizeTags(ctx, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	unit, err := menuItemUnit(req.Unit)
 	if err != nil {
-		return nil, fmt.Errorf("invalid station ID: %w", err)
+		return nil, err
 	}
 
-	_, err = s.repos.Station.GetByID(ctx, stationID)
+	plu, err := normalizePLU(req.PLU)
 	if err != nil {
-		return nil, fmt.Errorf("invalid station ID: %w", err)
+		return nil, err
 	}
 
 	// Create the menu item
 	item := models.MenuItem{
-		CategoryID:  req.CategoryID,
-		Name:        req.Name,
-		Price:       req.Price,
-		Available:   req.Available,
-		Description: req.Description,
-		ImagePath:   req.ImagePath,
+		CategoryID:     req.CategoryID,
+		Name:           req.Name,
+		Price:          req.Price,
+		Unit:           unit,
+		Available:      req.Available,
+		IsFeatured:     req.IsFeatured,
+		OrderingWeight: req.OrderingWeight,
+		Description:    req.Description,
+		ImagePath:      req.ImagePath,
+		KitchenNotes:   req.KitchenNotes,
+		PLU:            plu,
+		CreatedBy:      &userID,
+		Tags:           tags,
 	}
 
-	return s.repos.Menu.CreateItem(ctx, nil, item, req.ModifierIDs, stationID)
+	return s.repos.Menu.CreateItem(ctx, item, req.ModifierIDs, req.RequiredModifierIDs, stationID)
+}
+
+// itemStationID resolves the station a new item is routed to, an explicit station
+// overrides the category default
+func itemStationID(stationID string, category *models.MenuCategory) (uuid.UUID, error) {
+	if stationID != "" {
+		id, err := uuid.Parse(stationID)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("%w: invalid station ID: %w", ErrInvalidMenuItem, err)
+		}
+		return id, nil
+	}
+	if category.DefaultStationID != nil {
+		return *category.DefaultStationID, nil
+	}
+	return uuid.Nil, fmt.Errorf("%w: station ID is required when the category has no default station", ErrInvalidMenuItem)
 }
 
-// UpdateItem updates a menu item
-func (s *MenuService) UpdateItem(ctx context.Context, id uuid.UUID, req models.MenuItemRequest) (*models.MenuItem, error) {
+// UpdateItem updates a menu item on behalf of userID
+func (s *MenuService) UpdateItem(ctx context.Context, id, userID uuid.UUID, req models.MenuItemRequest) (*models.MenuItem, error) {
 	// Verify the item exists
 	_, err := s.repos.Menu.GetItemByID(ctx, id)
 	if err != nil {
@@ -117,11 +260,153 @@ func (s *MenuService) UpdateItem(ctx context.Context, id uuid.UUID, req models.M
 	// Verify the category exists
 	_, err = s.repos.Menu.GetCategoryByID(ctx, req.CategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid category ID: %w", err)
+		return nil, fmt.Errorf("%w: invalid category ID: %w", ErrInvalidMenuItem, err)
+	}
+
+	if err := validateRequiredModifiers(req); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.normalizeTags(ctx, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the station exists and can receive items
+	stationID, err := uuid.Parse(req.StationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid station ID: %w", ErrInvalidMenuItem, err)
+	}
+
+	station, err := s.repos.Station.GetByID(ctx, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid station ID: %w", ErrInvalidMenuItem, err)
+	}
+
+	if !station.IsActive {
+		return nil, ErrStationInactive
+	}
+
+	req.Unit, err = menuItemUnit(req.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Tags = tags
+	req.PLU, err = normalizePLU(req.PLU)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get the updated item
-	return s.repos.Menu.UpdateItem(ctx, nil, id, req)
+	return s.repos.Menu.UpdateItem(ctx, id, userID, req)
+}
+
+// SetItemAvailability marks a menu item available or 86'd on behalf of userID
+func (s *MenuService) SetItemAvailability(ctx context.Context, id, userID uuid.UUID, available bool) (*models.MenuItem, error) {
+	return s.PatchItem(ctx, id, userID, models.MenuItemPatchRequest{Available: &available})
+}
+
+// PatchItem updates only the fields present in patch on behalf of userID
+func (s *MenuService) PatchItem(ctx context.Context, id, userID uuid.UUID, patch models.MenuItemPatchRequest) (*models.MenuItem, error) {
+	item, err := s.repos.Menu.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("menu item not found: %w", err)
+	}
+
+	if patch.CategoryID != nil {
+		if _, err := s.repos.Menu.GetCategoryByID(ctx, *patch.CategoryID); err != nil {
+			return nil, fmt.Errorf("%w: invalid category ID: %w", ErrInvalidMenuItem, err)
+		}
+	}
+
+	if patch.Name != nil && strings.TrimSpace(*patch.Name) == "" {
+		return nil, fmt.Errorf("%w: name cannot be empty", ErrInvalidMenuItem)
+	}
+
+	if patch.Price != nil && *patch.Price < 0 {
+		return nil, fmt.Errorf("%w: price cannot be negative", ErrInvalidMenuItem)
+	}
+
+	if patch.PLU != nil {
+		plu, err := normalizePLU(patch.PLU)
+		if err != nil {
+			return nil, err
+		}
+		// An empty PLU clears it
+		if plu == nil {
+			plu = new(string)
+		}
+		patch.PLU = plu
+	}
+
+	if patch.Unit != nil {
+		unit, err := menuItemUnit(*patch.Unit)
+		if err != nil {
+			return nil, err
+		}
+		patch.Unit = &unit
+	}
+
+	// Fill in whichever modifier list was left out from the item so the
+	// required modifiers are always checked against the final set
+	if patch.ModifierIDs != nil || patch.RequiredModifierIDs != nil {
+		var modifierIDs, requiredIDs []uuid.UUID
+		for _, mim := range item.Modifiers {
+			modifierIDs = append(modifierIDs, mim.ModifierID)
+			if mim.Required {
+				requiredIDs = append(requiredIDs, mim.ModifierID)
+			}
+		}
+
+		if patch.ModifierIDs != nil {
+			modifierIDs = *patch.ModifierIDs
+		}
+
+		if patch.RequiredModifierIDs != nil {
+			requiredIDs = *patch.RequiredModifierIDs
+		} else {
+			// Keep existing required flags for modifiers that stay on the item
+			kept := requiredIDs[:0]
+			for _, requiredID := range requiredIDs {
+				if slices.Contains(modifierIDs, requiredID) {
+					kept = append(kept, requiredID)
+				}
+			}
+			requiredIDs = kept
+		}
+
+		err = validateRequiredModifiers(models.MenuItemRequest{
+			ModifierIDs:         modifierIDs,
+			RequiredModifierIDs: requiredIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		patch.ModifierIDs = &modifierIDs
+		patch.RequiredModifierIDs = &requiredIDs
+	}
+
+	if patch.StationID != nil {
+		station, err := s.repos.Station.GetByID(ctx, *patch.StationID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid station ID: %w", ErrInvalidMenuItem, err)
+		}
+		if !station.IsActive {
+			return nil, ErrStationInactive
+		}
+	}
+
+	if patch.Tags != nil {
+		tags, err := s.normalizeTags(ctx, *patch.Tags)
+		if err != nil {
+			return nil, err
+		}
+		patch.Tags = &tags
+	}
+
+	return s.repos.Menu.PatchItem(ctx, id, userID, patch)
 }
 
 // DeleteItem deletes a menu item
@@ -129,27 +414,282 @@ func (s *MenuService) DeleteItem(ctx context.Context, id uuid.UUID) error {
 	return s.repos.Menu.DeleteItem(ctx, id)
 }
 
+// BulkDeleteItems soft-deletes several menu items, skipping any on open orders
+func (s *MenuService) BulkDeleteItems(ctx context.Context, ids []uuid.UUID) ([]models.MenuItemDeleteResult, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("at least one menu item ID is required")
+	}
+
+	return s.repos.Menu.BulkDeleteItems(ctx, ids)
+}
+
+// AdjustPrices raises or lowers the prices of a category's items, or of the whole menu,
+// by a percentage on behalf of userID
+func (s *MenuService) AdjustPrices(ctx context.Context, userID uuid.UUID, req models.MenuPriceAdjustRequest) (*models.MenuPriceAdjustResult, error) {
+	if req.CategoryID == nil && !req.All {
+		return nil, fmt.Errorf("%w: category_id is required unless all is set", ErrInvalidPriceAdjustment)
+	}
+	if req.CategoryID != nil && req.All {
+		return nil, fmt.Errorf("%w: category_id and all cannot both be set", ErrInvalidPriceAdjustment)
+	}
+	if req.Percent == 0 || req.Percent < -50 || req.Percent > 100 {
+		return nil, fmt.Errorf("%w: percent must be between -50 and 100 and not 0", ErrInvalidPriceAdjustment)
+	}
+
+	if req.Rounding == "" {
+		req.Rounding = models.PriceRoundingCent
+	}
+	if _, ok := req.Rounding.Round(0); !ok {
+		return nil, fmt.Errorf("%w: rounding must be one of: cent, ten_cents, fifty_cents, whole", ErrInvalidPriceAdjustment)
+	}
+
+	if req.CategoryID != nil {
+		if _, err := s.repos.Menu.GetCategoryByID(ctx, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := s.repos.Menu.AdjustPrices(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MenuPriceAdjustResult{
+		CategoryID: req.CategoryID,
+		Percent:    req.Percent,
+		Rounding:   req.Rounding,
+		Changes:    changes,
+	}, nil
+}
+
+// GetPriceHistory lists a menu item's price changes, newest first
+func (s *MenuService) GetPriceHistory(ctx context.Context, id uuid.UUID) ([]models.MenuItemPriceChange, error) {
+	if _, err := s.repos.Menu.GetItemByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Menu.GetPriceHistory(ctx, id)
+}
+
 // GetModifiers retrieves all modifiers
 func (s *MenuService) GetModifiers(ctx context.Context) ([]models.Modifier, error) {
 	return s.repos.Menu.ListModifiers(ctx)
 }
 
+// SearchModifiers returns one page of modifiers with their options, filtered by name and
+// selection type. A zero limit uses the default page size.
+func (s *MenuService) SearchModifiers(ctx context.Context, filter models.ModifierFilter) (*models.ModifierPage, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize || filter.Offset < 0 {
+		return nil, ErrInvalidPagination
+	}
+
+	modifiers, total, err := s.repos.Menu.SearchModifiers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ModifierPage{
+		Modifiers: modifiers,
+		Total:     total,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+	}, nil
+}
+
 // GetModifier retrieves a modifier by ID
 func (s *MenuService) GetModifier(ctx context.Context, id uuid.UUID) (*models.Modifier, error) {
 	return s.repos.Menu.GetModifier(ctx, id)
 }
 
-// CreateModifier creates a new modifier
-func (s *MenuService) CreateModifier(ctx context.Context, name string, isMultiple bool, options []models.ModifierOption) (*models.Modifier, error) {
-	return s.repos.Menu.CreateModifier(ctx, name, isMultiple, options)
+// CreateModifier creates a new modifier on behalf of userID
+func (s *MenuService) CreateModifier(ctx context.Context, userID uuid.UUID, req models.ModifierRequest) (*models.Modifier, error) {
+	modifier, options, err := modifierFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.Menu.CreateModifier(ctx, userID, modifier, options)
+}
+
+// UpdateModifier updates a modifier on behalf of userID, replacing its options
+func (s *MenuService) UpdateModifier(ctx context.Context, id, userID uuid.UUID, req models.ModifierRequest) (*models.Modifier, error) {
+	modifier, options, err := modifierFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Menu.GetModifier(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repos.Menu.UpdateModifier(ctx, id, userID, modifier, options)
+}
+
+// modifierFromRequest validates a modifier request and splits it into the modifier and its options
+func modifierFromRequest(req models.ModifierRequest) (models.Modifier, []models.ModifierOption, error) {
+	modifier := models.Modifier{
+		Name:          strings.TrimSpace(req.Name),
+		IsMultiple:    req.IsMultiple,
+		MinSelections: req.MinSelections,
+		MaxSelections: req.MaxSelections,
+	}
+	if modifier.Name == "" || len(modifier.Name) > 100 {
+		return models.Modifier{}, nil, fmt.Errorf("%w: name must be 1 to 100 characters", ErrInvalidModifier)
+	}
+	if err := normalizeSelectionLimits(&modifier); err != nil {
+		return models.Modifier{}, nil, err
+	}
+
+	options := make([]models.ModifierOption, 0, len(req.Options))
+	for _, o := range req.Options {
+		if strings.TrimSpace(o.Name) == "" {
+			return models.Modifier{}, nil, fmt.Errorf("%w: option name is required", ErrInvalidModifier)
+		}
+		if o.AdjustmentType != "" && o.AdjustmentType != models.AdjustmentTypeAbsolute && o.AdjustmentType != models.AdjustmentTypePercent {
+			return models.Modifier{}, nil, fmt.Errorf("%w: adjustment_type must be absolute or percent", ErrInvalidModifier)
+		}
+		options = append(options, models.ModifierOption{
+			Name:            strings.TrimSpace(o.Name),
+			PriceAdjustment: o.PriceAdjustment,
+			AdjustmentType:  o.AdjustmentType,
+		})
+	}
+	if err := validateModifierOptions(options); err != nil {
+		return models.Modifier{}, nil, err
+	}
+
+	return modifier, options, nil
+}
+
+// normalizeSelectionLimits checks a modifier's selection limits, capping single-select
+// modifiers at one option
+func normalizeSelectionLimits(modifier *models.Modifier) error {
+	if !modifier.IsMultiple {
+		if modifier.MaxSelections != nil && *modifier.MaxSelections != 1 {
+			return fmt.Errorf("%w: a single-select modifier allows exactly one option", ErrInvalidModifier)
+		}
+		one := 1
+		modifier.MaxSelections = &one
+	}
+
+	if modifier.MinSelections < 0 {
+		return fmt.Errorf("%w: min_selections cannot be negative", ErrInvalidModifier)
+	}
+	if modifier.MaxSelections != nil && *modifier.MaxSelections < max(modifier.MinSelections, 1) {
+		return fmt.Errorf("%w: max_selections must be at least min_selections and at least 1", ErrInvalidModifier)
+	}
+
+	return nil
+}
+
+// validateModifierOptions caps a modifier's options and rejects repeated names, ignoring case
+func validateModifierOptions(options []models.ModifierOption) error {
+	if len(options) > maxModifierOptions {
+		return fmt.Errorf("%w: %d given, at most %d allowed", ErrTooManyOptions, len(options), maxModifierOptions)
+	}
+
+	seen := make(map[string]bool, len(options))
+	for _, o := range options {
+		name := strings.ToLower(strings.TrimSpace(o.Name))
+		if seen[name] {
+			return fmt.Errorf("%w: option %q is listed more than once", ErrInvalidModifier, o.Name)
+		}
+		seen[name] = true
+	}
+
+	return nil
 }
 
-// UpdateModifier updates a modifier
-func (s *MenuService) UpdateModifier(ctx context.Context, id uuid.UUID, name string, isMultiple bool, options []models.ModifierOption) (*models.Modifier, error) {
-	return s.repos.Menu.UpdateModifier(ctx, id, name, isMultiple, options)
+// GetModifierUsage lists the menu items using a modifier, so it can be detached before deletion
+func (s *MenuService) GetModifierUsage(ctx context.Context, id uuid.UUID) ([]models.ModifierUsage, error) {
+	if _, err := s.repos.Menu.GetModifier(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Menu.GetModifierUsage(ctx, id)
 }
 
 // DeleteModifier deletes a modifier
 func (s *MenuService) DeleteModifier(ctx context.Context, id uuid.UUID) error {
 	return s.repos.Menu.DeleteModifier(ctx, id)
 }
+
+// normalizePLU trims a PLU, a blank one means the item has none
+func normalizePLU(plu *string) (*string, error) {
+	if plu == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*plu)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if len(trimmed) > 32 {
+		return nil, fmt.Errorf("%w: PLU is longer than 32 characters", ErrInvalidMenuItem)
+	}
+	return &trimmed, nil
+}
+
+// validateRequiredModifiers checks that required modifiers are attached to the item
+func validateRequiredModifiers(req models.MenuItemRequest) error {
+	for _, requiredID := range req.RequiredModifierIDs {
+		if !slices.Contains(req.ModifierIDs, requiredID) {
+			return fmt.Errorf("%w: required modifier %s is not one of the item's modifiers", ErrInvalidMenuItem, requiredID)
+		}
+	}
+	return nil
+}
+
+// normalizeTags lowercases, trims and de-duplicates tags, checking them against the
+// managed tag list when one has been set up
+func (s *MenuService) normalizeTags(ctx context.Context, tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > 50 {
+			return nil, fmt.Errorf("%w: tag %q is longer than 50 characters", ErrInvalidMenuItem, tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) == 0 {
+		return normalized, nil
+	}
+
+	managed, err := s.repos.Menu.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(managed) == 0 {
+		return normalized, nil
+	}
+
+	allowed := make(map[string]bool, len(managed))
+	for _, tag := range managed {
+		allowed[tag] = true
+	}
+	for _, tag := range normalized {
+		if !allowed[tag] {
+			return nil, fmt.Errorf("%w: unknown tag %q", ErrInvalidMenuItem, tag)
+		}
+	}
+
+	return normalized, nil
+}
+
+// menuItemUnit validates a requested unit, defaulting to each
+func menuItemUnit(unit models.MenuItemUnit) (models.MenuItemUnit, error) {
+	switch unit {
+	case "":
+		return models.MenuItemUnitEach, nil
+	case models.MenuItemUnitEach, models.MenuItemUnitKg:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("%w: unit must be one of: each, kg", ErrInvalidMenuItem)
+	}
+}
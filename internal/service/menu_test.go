@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+func TestItemStationID(t *testing.T) {
+	grill, pizza := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		stationID string
+		category  models.MenuCategory
+		want      uuid.UUID
+		wantErr   error
+	}{
+		{"inherits the category default", "", models.MenuCategory{DefaultStationID: &pizza}, pizza, nil},
+		{"overrides the category default", grill.String(), models.MenuCategory{DefaultStationID: &pizza}, grill, nil},
+		{"explicit without a default", grill.String(), models.MenuCategory{}, grill, nil},
+		{"neither", "", models.MenuCategory{}, uuid.Nil, ErrInvalidMenuItem},
+		{"invalid station", "grill", models.MenuCategory{DefaultStationID: &pizza}, uuid.Nil, ErrInvalidMenuItem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := itemStationID(tt.stationID, &tt.category)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("station = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
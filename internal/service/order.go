@@ -0,0 +1,742 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+var (
+	// ErrOrderNotOpen is returned when modifying an order that is completed or cancelled
+	ErrOrderNotOpen = errors.New("order is not open")
+
+	// ErrMissingRequiredModifier is returned when an order item omits a required modifier
+	// or chooses fewer options than a modifier's minimum
+	ErrMissingRequiredModifier = errors.New("missing required modifier")
+
+	// ErrTooManyModifierOptions is returned when an order item chooses more options than a modifier allows
+	ErrTooManyModifierOptions = errors.New("too many modifier options")
+
+	// ErrItemNotWaiting is returned when rushing an item that is already cooked or cancelled
+	ErrItemNotWaiting = errors.New("order item is not waiting to be cooked")
+
+	// ErrDuplicateModifierOption is returned when an order item chooses the same option twice
+	ErrDuplicateModifierOption = errors.New("modifier option chosen more than once")
+
+	// ErrInvalidStatus is returned for a status value that doesn't exist
+	ErrInvalidStatus = errors.New("invalid status")
+
+	// ErrInvalidStatusTransition is returned when the current status can't move to the requested one
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+	// ErrInvalidOrderNumberSearch is returned for an order number fragment that is empty or has unexpected characters
+	ErrInvalidOrderNumberSearch = errors.New("order number search must be 1 to 20 letters, digits or dashes")
+
+	// ErrInvalidOrderItems is matched by an OrderItemsError
+	ErrInvalidOrderItems = errors.New("order has invalid items")
+
+	// ErrOrderOnHold is returned when holding an order that is already on hold
+	ErrOrderOnHold = errors.New("order is already on hold")
+
+	// ErrOrderNotOnHold is returned when releasing an order that isn't on hold
+	ErrOrderNotOnHold = errors.New("order is not on hold")
+
+	// ErrOrderNotCompleted is returned when reopening an order that isn't completed
+	ErrOrderNotCompleted = errors.New("only completed orders can be reopened")
+
+	// ErrReopenWindowExpired is returned when reopening an order completed more than reopenWindow ago
+	ErrReopenWindowExpired = fmt.Errorf("orders can only be reopened within %s of completion", reopenWindow)
+
+	// ErrInvalidCustomer is returned for a customer name or phone number that can't be stored
+	ErrInvalidCustomer = errors.New("invalid customer details")
+
+	// ErrInvalidOrderNumberFormat is returned for an order number template that can't be rendered
+	ErrInvalidOrderNumberFormat = errors.New("invalid order number format")
+)
+
+// OrderItemsError lists every item of an order request that can't be ordered
+type OrderItemsError struct {
+	Problems []models.OrderItemProblem
+}
+
+func (e *OrderItemsError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = fmt.Sprintf("item %d: %s", p.Index, p.Message)
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidOrderItems, strings.Join(messages, "; "))
+}
+
+func (e *OrderItemsError) Unwrap() error {
+	return ErrInvalidOrderItems
+}
+
+// phonePattern loosely matches a phone number: digits with optional spaces, dashes,
+// brackets and a leading +
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ()-]+$`)
+
+// customerDetails trims the optional customer name and phone of an order, blank values
+// become nil. Phones need 6 to 15 digits, formatting characters aside.
+func customerDetails(name, phone *string) (*string, *string, error) {
+	trimmed := func(s *string) *string {
+		if s == nil {
+			return nil
+		}
+		t := strings.TrimSpace(*s)
+		if t == "" {
+			return nil
+		}
+		return &t
+	}
+	name, phone = trimmed(name), trimmed(phone)
+
+	if name != nil && utf8.RuneCountInString(*name) > 100 {
+		return nil, nil, fmt.Errorf("%w: customer name is longer than 100 characters", ErrInvalidCustomer)
+	}
+
+	if phone != nil {
+		digits := 0
+		for _, r := range *phone {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		if len(*phone) > 20 || !phonePattern.MatchString(*phone) || digits < 6 || digits > 15 {
+			return nil, nil, fmt.Errorf("%w: %q is not a phone number", ErrInvalidCustomer, *phone)
+		}
+	}
+
+	return name, phone, nil
+}
+
+// DefaultOrderNumberFormat renders order numbers like 20240131-007
+const DefaultOrderNumberFormat = "{date}-{seq}"
+
+// orderNumberFormat is the template CreateOrder renders order numbers with, see SetOrderNumberFormat
+var orderNumberFormat = DefaultOrderNumberFormat
+
+// SetOrderNumberFormat validates and sets the order number template, call it before serving requests.
+// {seq} is required and padded to three digits, {date} is the business date as YYYYMMDD, see BusinessDate.
+// {seq} counts per day when the template has {date}, otherwise it never resets so numbers stay unique.
+func SetOrderNumberFormat(format string) error {
+	if !strings.Contains(format, "{seq}") {
+		return fmt.Errorf("%w: %q must contain {seq}", ErrInvalidOrderNumberFormat, format)
+	}
+
+	rest := strings.NewReplacer("{seq}", "", "{date}", "").Replace(format)
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("%w: %q has an unknown placeholder, only {seq} and {date} are supported", ErrInvalidOrderNumberFormat, format)
+	}
+	if len(renderOrderNumber(format, time.Now(), 999)) > 50 {
+		return fmt.Errorf("%w: %q renders longer than 50 characters", ErrInvalidOrderNumberFormat, format)
+	}
+
+	orderNumberFormat = format
+	return nil
+}
+
+// renderOrderNumber fills an order number template for the given day and sequence
+func renderOrderNumber(format string, day time.Time, seq int) string {
+	return strings.NewReplacer(
+		"{seq}", fmt.Sprintf("%03d", seq),
+		"{date}", day.Format("20060102"),
+	).Replace(format)
+}
+
+// OrderService handles order-related business logic
+type OrderService struct {
+	repos *repository.Repositories
+}
+
+// NewOrderService creates a new order service
+func NewOrderService(repos *repository.Repositories) *OrderService {
+	return &OrderService{
+		repos: repos,
+	}
+}
+
+// CreateOrder creates a new order taken by the given user
+func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, req models.OrderRequest) (*models.Order, error) {
+	return s.createOrder(ctx, userID, req, models.OrderSourcePOS, nil, nil)
+}
+
+// CreateOnlineOrder creates an order a third-party platform sent, recorded against userID.
+// externalID is the platform's own order ID, a platform can only send each one once.
+func (s *OrderService) CreateOnlineOrder(ctx context.Context, userID uuid.UUID, req models.OrderRequest, provider, externalID string) (*models.Order, error) {
+	return s.createOrder(ctx, userID, req, models.OrderSourceOnline, &provider, &externalID)
+}
+
+// createOrder validates and creates an order placed through source
+func (s *OrderService) createOrder(ctx context.Context, userID uuid.UUID, req models.OrderRequest, source models.OrderSource, provider, externalID *string) (*models.Order, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.New("order must contain at least one item")
+	}
+
+	customerName, customerPhone, err := customerDetails(req.CustomerName, req.CustomerPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every item before creating anything so all problems are reported together
+	if err := s.validateOrderItems(ctx, req.Items); err != nil {
+		return nil, err
+	}
+
+	items := req.Items
+	if req.MergeIdenticalItems {
+		items = mergeIdenticalItems(items)
+	}
+
+	now := time.Now()
+	day := BusinessDate(now)
+
+	// Generate the order number from the daily sequence, or the running one for formats without a date
+	format := orderNumberFormat
+	var seq int
+	if strings.Contains(format, "{date}") {
+		seq, err = s.repos.Order.NextOrderSequence(ctx, day)
+	} else {
+		seq, err = s.repos.Order.NextContinuousOrderSequence(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate order number: %w", err)
+	}
+
+	order := models.Order{
+		UserID:        userID,
+		OrderNumber:   renderOrderNumber(format, day, seq),
+		Status:        models.OrderStatusNew,
+		Notes:         req.Notes,
+		OrderedAt:     now,
+		CustomerName:  customerName,
+		CustomerPhone: customerPhone,
+		Source:        source,
+		Provider:      provider,
+		ExternalID:    externalID,
+	}
+
+	return s.repos.Order.Create(ctx, order, items)
+}
+
+// GetOrder retrieves an order by ID
+func (s *OrderService) GetOrder(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	return s.repos.Order.GetByID(ctx, id)
+}
+
+// ListOpenOrders returns one page of orders that are neither completed nor cancelled.
+// A zero limit uses the default page size and an empty sort lists the oldest first.
+func (s *OrderService) ListOpenOrders(ctx context.Context, filter models.OpenOrderFilter) (*models.OrderPage, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.OpenOrderSortAge
+	case models.OpenOrderSortAge, models.OpenOrderSortNewest:
+	default:
+		return nil, fmt.Errorf("invalid sort: %s", filter.Sort)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize || filter.Offset < 0 {
+		return nil, ErrInvalidPagination
+	}
+
+	orders, total, err := s.repos.Order.ListOpen(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OrderPage{
+		Orders: orders,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// GetOrderHistory returns the orders placed in [start, end), newest first
+func (s *OrderService) GetOrderHistory(ctx context.Context, start, end time.Time) ([]models.Order, error) {
+	if end.Before(start) {
+		return nil, ErrInvalidDateRange
+	}
+
+	return s.repos.Order.GetOrderHistory(ctx, start, end)
+}
+
+// orderSearchWindow is how far back order number searches look
+const orderSearchWindow = 7 * 24 * time.Hour
+
+// orderSearchLimit caps how many orders an order number search returns
+const orderSearchLimit = 20
+
+// SearchByOrderNumber finds recent orders whose number contains fragment, those ending with it first
+func (s *OrderService) SearchByOrderNumber(ctx context.Context, fragment string) ([]models.Order, error) {
+	if len(fragment) == 0 || len(fragment) > 20 {
+		return nil, ErrInvalidOrderNumberSearch
+	}
+	for _, c := range fragment {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+			return nil, ErrInvalidOrderNumberSearch
+		}
+	}
+
+	return s.repos.Order.SearchByOrderNumber(ctx, fragment, time.Now().Add(-orderSearchWindow), orderSearchLimit)
+}
+
+// UpdateNotes replaces the notes on an open order on behalf of userID
+func (s *OrderService) UpdateNotes(ctx context.Context, id, userID uuid.UUID, notes *string) (*models.Order, error) {
+	order, err := s.repos.Order.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("order not found: %w", err)
+	}
+
+	if !order.IsOpen() {
+		return nil, ErrOrderNotOpen
+	}
+
+	err = s.repos.Order.UpdateNotes(ctx, id, userID, notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update notes: %w", err)
+	}
+
+	return s.repos.Order.GetByID(ctx, id)
+}
+
+// SetRush flags or unflags an open order as rushed so stations see its items first
+func (s *OrderService) SetRush(ctx context.Context, id uuid.UUID, rush bool) (*models.Order, error) {
+	order, err := s.repos.Order.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.IsOpen() {
+		return nil, ErrOrderNotOpen
+	}
+
+	if err := s.repos.Order.SetRush(ctx, id, rush); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Order.GetByID(ctx, id)
+}
+
+// SetItemRush flags or unflags one item of an open order as rushed, so it jumps the queue at
+// its station without rushing the rest of the order. Only items not yet cooked can be rushed.
+func (s *OrderService) SetItemRush(ctx context.Context, orderID, itemID uuid.UUID, rush bool) (*models.OrderItem, error) {
+	item, err := s.repos.Order.GetOrderItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OrderID != orderID {
+		return nil, fmt.Errorf("order item %w", repository.ErrNotFound)
+	}
+
+	order, err := s.repos.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsOpen() {
+		return nil, ErrOrderNotOpen
+	}
+	if item.Status != models.OrderItemStatusPending && item.Status != models.OrderItemStatusInProgress {
+		return nil, ErrItemNotWaiting
+	}
+
+	if err := s.repos.Order.SetItemRush(ctx, itemID, rush); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Order.GetOrderItem(ctx, itemID)
+}
+
+// SetHold puts an open order on hold so stations don't see its items, or releases it so
+// they do. Unlike coursing it covers the whole order.
+func (s *OrderService) SetHold(ctx context.Context, id uuid.UUID, hold bool) (*models.Order, error) {
+	order, err := s.repos.Order.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkHold(order, hold); err != nil {
+		return nil, err
+	}
+
+	if err := s.repos.Order.SetHold(ctx, id, hold); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Order.GetByID(ctx, id)
+}
+
+// checkHold returns why an order can't be put on hold, or released when hold is false
+func checkHold(order *models.Order, hold bool) error {
+	if !order.IsOpen() {
+		return ErrOrderNotOpen
+	}
+	if hold && order.IsHeld() {
+		return ErrOrderOnHold
+	}
+	if !hold && !order.IsHeld() {
+		return ErrOrderNotOnHold
+	}
+	return nil
+}
+
+// GetKitchenActive returns every order being cooked, oldest first, with its items grouped
+// by station and its progress, so an expo screen has one view of the kitchen
+func (s *OrderService) GetKitchenActive(ctx context.Context) ([]models.KitchenOrder, error) {
+	items, err := s.repos.Order.GetKitchenItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	orders := []models.KitchenOrder{}
+	for _, item := range items {
+		// Items arrive grouped by order, then by station
+		if len(orders) == 0 || orders[len(orders)-1].OrderID != item.OrderID {
+			orders = append(orders, models.KitchenOrder{
+				OrderID:     item.OrderID,
+				OrderNumber: item.OrderNumber,
+				Status:      item.OrderStatus,
+				IsRush:      item.IsRush,
+				OrderedAt:   item.OrderedAt,
+			})
+		}
+		order := &orders[len(orders)-1]
+
+		if n := len(order.Stations); n == 0 || order.Stations[n-1].StationID != item.StationID {
+			order.Stations = append(order.Stations, models.KitchenStationItems{
+				StationID:   item.StationID,
+				StationName: item.StationName,
+			})
+		}
+		station := &order.Stations[len(order.Stations)-1]
+		station.Items = append(station.Items, item.OrderItem)
+
+		order.ItemsTotal++
+		if item.Status == models.OrderItemStatusReady || item.Status == models.OrderItemStatusCompleted {
+			order.ItemsCompleted++
+			continue
+		}
+
+		sent := item.CreatedAt
+		if item.SentToStationAt != nil {
+			sent = *item.SentToStationAt
+		}
+		if age := int(now.Sub(sent).Seconds()); age > order.OldestItemAge {
+			order.OldestItemAge = age
+		}
+	}
+
+	for i := range orders {
+		orders[i].Progress = float64(orders[i].ItemsCompleted) / float64(orders[i].ItemsTotal)
+	}
+
+	return orders, nil
+}
+
+// UpdateStatus moves an order to a new status if the transition is allowed
+func (s *OrderService) UpdateStatus(ctx context.Context, id uuid.UUID, status models.OrderStatus) (*models.Order, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+
+	order, err := s.repos.Order.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.Status.CanTransitionTo(status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, order.Status, status)
+	}
+
+	if err := s.repos.Order.TransitionStatus(ctx, id, order.Status, status); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Order.GetByID(ctx, id)
+}
+
+// reopenWindow is how long after completion an order can still be reopened
+const reopenWindow = 30 * time.Minute
+
+// checkReopen reports whether an order can be reopened at now
+func checkReopen(order *models.Order, now time.Time) error {
+	if order.Status != models.OrderStatusCompleted {
+		return ErrOrderNotCompleted
+	}
+	if order.CompletedAt == nil || now.Sub(*order.CompletedAt) > reopenWindow {
+		return ErrReopenWindowExpired
+	}
+	return nil
+}
+
+// ReopenOrder moves an order completed by mistake back to in progress on behalf of userID,
+// putting the items completed last back to ready
+func (s *OrderService) ReopenOrder(ctx context.Context, id, userID uuid.UUID, reason string) (*models.Order, error) {
+	order, err := s.repos.Order.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReopen(order, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repos.Order.ReopenOrder(ctx, id, userID, reason); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Order.GetByID(ctx, id)
+}
+
+// UpdateItemStatus moves an item on the given order to a new status if the transition is allowed.
+// Cancelling an item voids it on behalf of userID so the order total is adjusted.
+func (s *OrderService) UpdateItemStatus(ctx context.Context, orderID, itemID, userID uuid.UUID, req models.OrderItemStatusRequest) (*models.OrderItem, error) {
+	if !req.Status.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStatus, req.Status)
+	}
+
+	item, err := s.repos.Order.GetOrderItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OrderID != orderID {
+		return nil, fmt.Errorf("order item %w", repository.ErrNotFound)
+	}
+
+	if !item.Status.CanTransitionTo(req.Status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, item.Status, req.Status)
+	}
+
+	switch req.Status {
+	case models.OrderItemStatusCancelled:
+		err = s.repos.Order.VoidItem(ctx, itemID, userID, req.Reason)
+	case models.OrderItemStatusInProgress:
+		if item.Status == models.OrderItemStatusReady || item.Status == models.OrderItemStatusCompleted {
+			err = s.repos.Order.RecallItem(ctx, itemID)
+		} else {
+			err = s.repos.Order.UpdateItemStatus(ctx, itemID, item.Status, req.Status)
+		}
+	default:
+		err = s.repos.Order.UpdateItemStatus(ctx, itemID, item.Status, req.Status)
+	}
+	if errors.Is(err, repository.ErrItemStatusChanged) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatusTransition, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repos.Order.GetOrderItem(ctx, itemID)
+}
+
+// RecalculateTotal recomputes an order's total from its lines on behalf of userID
+func (s *OrderService) RecalculateTotal(ctx context.Context, id, userID uuid.UUID) (*models.OrderTotalRecalculation, error) {
+	previous, total, err := s.repos.Order.RecalculateTotal(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OrderTotalRecalculation{
+		OrderID:       id,
+		PreviousTotal: previous,
+		Total:         total,
+		Changed:       previous != total,
+	}, nil
+}
+
+// GetTimeline returns the chronological history of an order and its items
+func (s *OrderService) GetTimeline(ctx context.Context, id uuid.UUID) ([]models.OrderTimelineEvent, error) {
+	// Distinguish a missing order from one with no history
+	if _, err := s.repos.Order.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Order.GetTimeline(ctx, id)
+}
+
+// validateOrderItems checks that every requested item exists, is available, can be routed to a
+// station and has valid modifier selections. It returns an OrderItemsError listing each problem.
+func (s *OrderService) validateOrderItems(ctx context.Context, items []models.OrderItemRequest) error {
+	var problems []models.OrderItemProblem
+	problem := func(i int, item models.OrderItemRequest, format string, args ...interface{}) {
+		problems = append(problems, models.OrderItemProblem{Index: i, MenuItemID: item.MenuItemID, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for i, item := range items {
+		menuItem, err := s.repos.Menu.GetItemByID(ctx, item.MenuItemID)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && menuItem.DeletedAt != nil) {
+			problem(i, item, "menu item not found")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !menuItem.Available {
+			problem(i, item, "%s is unavailable", menuItem.Name)
+		}
+		if !menuItem.Unit.AllowsQuantity(item.Quantity) {
+			problem(i, item, "%v %s is not a valid quantity of %s", item.Quantity, menuItem.Unit, menuItem.Name)
+		}
+
+		if _, err := s.repos.Order.ResolveStation(ctx, item.MenuItemID); errors.Is(err, repository.ErrNoStationConfigured) {
+			problem(i, item, "no active station is configured for %s", menuItem.Name)
+		} else if err != nil {
+			return err
+		}
+
+		if unknown := unknownOptions(menuItem.Modifiers, item.Modifiers); len(unknown) > 0 {
+			problem(i, item, "modifier option %s is not offered for %s", unknown[0], menuItem.Name)
+		}
+		if err := checkModifierSelections(menuItem.Modifiers, item); err != nil {
+			problem(i, item, "%s", err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &OrderItemsError{Problems: problems}
+	}
+	return nil
+}
+
+// unknownOptions returns the chosen options that aren't in any of the item's modifier groups
+func unknownOptions(modifiers []models.MenuItemModifier, chosen []models.OrderModifierRequest) []uuid.UUID {
+	offered := make(map[uuid.UUID]bool)
+	for _, mim := range modifiers {
+		for _, option := range mim.Modifier.Options {
+			offered[option.ID] = true
+		}
+	}
+
+	var unknown []uuid.UUID
+	for _, mod := range chosen {
+		if !offered[mod.OptionID] {
+			unknown = append(unknown, mod.OptionID)
+		}
+	}
+	return unknown
+}
+
+// checkModifierSelections checks the number of options chosen from each of the
+// item's modifiers is within that modifier's selection bounds. An option is priced once
+// per time it is listed, so listing one twice is rejected rather than counted once.
+func checkModifierSelections(modifiers []models.MenuItemModifier, item models.OrderItemRequest) error {
+	selected := make(map[uuid.UUID]bool, len(item.Modifiers))
+	for _, mod := range item.Modifiers {
+		if selected[mod.OptionID] {
+			return fmt.Errorf("%w: %s", ErrDuplicateModifierOption, mod.OptionID)
+		}
+		selected[mod.OptionID] = true
+	}
+
+	for _, mim := range modifiers {
+		chosen := 0
+		for _, option := range mim.Modifier.Options {
+			if selected[option.ID] {
+				chosen++
+			}
+		}
+
+		least, most := mim.SelectionBounds()
+		switch {
+		case chosen < least && least == 1:
+			return fmt.Errorf("%w: choose an option for %s", ErrMissingRequiredModifier, mim.Modifier.Name)
+		case chosen < least:
+			return fmt.Errorf("%w: choose at least %d options for %s", ErrMissingRequiredModifier, least, mim.Modifier.Name)
+		case most > 0 && chosen > most && most == 1:
+			return fmt.Errorf("%w: choose only one option for %s", ErrTooManyModifierOptions, mim.Modifier.Name)
+		case most > 0 && chosen > most:
+			return fmt.Errorf("%w: choose at most %d options for %s", ErrTooManyModifierOptions, most, mim.Modifier.Name)
+		}
+	}
+
+	return nil
+}
+
+// CancelStaleOrders cancels new orders older than maxAge that never reached a station
+func (s *OrderService) CancelStaleOrders(ctx context.Context, maxAge time.Duration) ([]models.Order, error) {
+	return s.repos.Order.CancelStaleOrders(ctx, time.Now().Add(-maxAge))
+}
+
+// RunAutoCancel cancels stale orders every interval until ctx is cancelled,
+// broadcasting an order.update for each one
+func (s *OrderService) RunAutoCancel(ctx context.Context, hub websockets.Broadcaster, maxAge, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelled, err := s.CancelStaleOrders(ctx, maxAge)
+			if err != nil {
+				log.Printf("Failed to auto-cancel stale orders: %v", err)
+				continue
+			}
+
+			for _, order := range cancelled {
+				log.Printf("Auto-cancelled stale order %s", order.OrderNumber)
+				data := models.OrderUpdate{UpdateType: "auto_cancel", ID: order.ID, Status: models.OrderStatusCancelled}
+				if err := hub.BroadcastMessage(websockets.TypeOrderUpdate, data); err != nil {
+					log.Printf("Failed to broadcast order update: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// mergeIdenticalItems combines items with the same menu item, modifier options and
+// instructions into one line with the summed quantity, keeping first-seen order
+func mergeIdenticalItems(items []models.OrderItemRequest) []models.OrderItemRequest {
+	merged := make([]models.OrderItemRequest, 0, len(items))
+	index := make(map[string]int, len(items))
+
+	for _, item := range items {
+		key := orderItemKey(item)
+		if i, ok := index[key]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged
+}
+
+// orderItemKey identifies an item configuration and seat regardless of modifier order
+func orderItemKey(item models.OrderItemRequest) string {
+	options := make([]string, 0, len(item.Modifiers))
+	for _, mod := range item.Modifiers {
+		options = append(options, mod.OptionID.String())
+	}
+	sort.Strings(options)
+
+	instructions := ""
+	if item.SpecialInstructions != nil {
+		instructions = *item.SpecialInstructions
+	}
+
+	seat := ""
+	if item.Seat != nil {
+		seat = strconv.Itoa(*item.Seat)
+	}
+
+	return item.MenuItemID.String() + "|" + strings.Join(options, ",") + "|" + instructions + "|" + seat
+}
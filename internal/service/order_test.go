@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+func TestCheckReopen(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) *time.Time {
+		v := now.Add(-ago)
+		return &v
+	}
+
+	tests := []struct {
+		name  string
+		order models.Order
+		want  error
+	}{
+		{"just completed", models.Order{Status: models.OrderStatusCompleted, CompletedAt: at(time.Minute)}, nil},
+		{"at the window", models.Order{Status: models.OrderStatusCompleted, CompletedAt: at(reopenWindow)}, nil},
+		{"past the window", models.Order{Status: models.OrderStatusCompleted, CompletedAt: at(reopenWindow + time.Second)}, ErrReopenWindowExpired},
+		{"no completion time", models.Order{Status: models.OrderStatusCompleted}, ErrReopenWindowExpired},
+		{"still open", models.Order{Status: models.OrderStatusInProgress}, ErrOrderNotCompleted},
+		{"cancelled", models.Order{Status: models.OrderStatusCancelled, CompletedAt: at(time.Minute)}, ErrOrderNotCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkReopen(&tt.order, now); !errors.Is(err, tt.want) {
+				t.Errorf("checkReopen() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHold(t *testing.T) {
+	held := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	released := held.Add(10 * time.Minute)
+
+	tests := []struct {
+		name  string
+		order models.Order
+		hold  bool
+		want  error
+	}{
+		{"hold new order", models.Order{Status: models.OrderStatusNew}, true, nil},
+		{"hold held order", models.Order{Status: models.OrderStatusNew, HeldAt: &held}, true, ErrOrderOnHold},
+		{"hold released order", models.Order{Status: models.OrderStatusInProgress, HeldAt: &held, ReleasedAt: &released}, true, nil},
+		{"release held order", models.Order{Status: models.OrderStatusNew, HeldAt: &held}, false, nil},
+		{"release order never held", models.Order{Status: models.OrderStatusNew}, false, ErrOrderNotOnHold},
+		{"release released order", models.Order{Status: models.OrderStatusNew, HeldAt: &held, ReleasedAt: &released}, false, ErrOrderNotOnHold},
+		{"hold completed order", models.Order{Status: models.OrderStatusCompleted}, true, ErrOrderNotOpen},
+		{"release cancelled order", models.Order{Status: models.OrderStatusCancelled, HeldAt: &held}, false, ErrOrderNotOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkHold(&tt.order, tt.hold); !errors.Is(err, tt.want) {
+				t.Errorf("checkHold() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckModifierSelections(t *testing.T) {
+	cheese, bacon := uuid.New(), uuid.New()
+	two := 2
+	toppings := []models.MenuItemModifier{{
+		Modifier: &models.Modifier{
+			Name:          "Toppings",
+			IsMultiple:    true,
+			MaxSelections: &two,
+			Options:       []models.ModifierOption{{ID: cheese}, {ID: bacon}},
+		},
+	}}
+	chosen := func(ids ...uuid.UUID) models.OrderItemRequest {
+		var req models.OrderItemRequest
+		for _, id := range ids {
+			req.Modifiers = append(req.Modifiers, models.OrderModifierRequest{OptionID: id})
+		}
+		return req
+	}
+
+	tests := []struct {
+		name string
+		item models.OrderItemRequest
+		want error
+	}{
+		{"within limit", chosen(cheese, bacon), nil},
+		{"none chosen", chosen(), nil},
+		{"same option twice", chosen(cheese, cheese), ErrDuplicateModifierOption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkModifierSelections(toppings, tt.item); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderOrderNumber(t *testing.T) {
+	day := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		seq    int
+		want   string
+	}{
+		{DefaultOrderNumberFormat, 7, "20240131-007"},
+		{DefaultOrderNumberFormat, 1234, "20240131-1234"},
+		{"{seq}", 42, "042"}, // Continuous, the sequence never resets
+		{"A-{seq}", 1000, "A-1000"},
+		{"{date}/{seq}/{date}", 1, "20240131/001/20240131"},
+	}
+	for _, tt := range tests {
+		if got := renderOrderNumber(tt.format, day, tt.seq); got != tt.want {
+			t.Errorf("renderOrderNumber(%q, %d) = %q, want %q", tt.format, tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestSetOrderNumberFormat(t *testing.T) {
+	t.Cleanup(func() { orderNumberFormat = DefaultOrderNumberFormat })
+
+	tests := []struct {
+		format string
+		want   error
+	}{
+		{DefaultOrderNumberFormat, nil},
+		{"{seq}", nil},
+		{"POS-{date}-{seq}", nil},
+		{"", ErrInvalidOrderNumberFormat},
+		{"{date}", ErrInvalidOrderNumberFormat},
+		{"{seq}-{time}", ErrInvalidOrderNumberFormat},
+		{"{seq}}", ErrInvalidOrderNumberFormat},
+		{strings.Repeat("x", 48) + "{seq}", ErrInvalidOrderNumberFormat},
+	}
+	for _, tt := range tests {
+		orderNumberFormat = DefaultOrderNumberFormat
+		err := SetOrderNumberFormat(tt.format)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("SetOrderNumberFormat(%q) = %v, want %v", tt.format, err, tt.want)
+			continue
+		}
+
+		want := tt.format
+		if err != nil {
+			want = DefaultOrderNumberFormat
+		}
+		if orderNumberFormat != want {
+			t.Errorf("after SetOrderNumberFormat(%q) the format is %q, want %q", tt.format, orderNumberFormat, want)
+		}
+	}
+}
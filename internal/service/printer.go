@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+var (
+	// ErrInvalidPrinterPurpose is returned for a default purpose other than receipt, kitchen or label
+	ErrInvalidPrinterPurpose = errors.New("purpose must be one of: receipt, kitchen, label")
+
+	// ErrInvalidPrinter is returned when a printer request fails validation
+	ErrInvalidPrinter = errors.New("invalid printer")
+)
+
+// PrinterService handles printer-related business logic
+type PrinterService struct {
+	repos *repository.Repositories
+}
+
+// NewPrinterService creates a new printer service
+func NewPrinterService(repos *repository.Repositories) *PrinterService {
+	return &PrinterService{
+		repos: repos,
+	}
+}
+
+// CreatePrinter adds a printer on behalf of userID. A printer created as the default for a
+// purpose takes over from the previous one.
+func (s *PrinterService) CreatePrinter(ctx context.Context, userID uuid.UUID, req models.PrinterRequest) (*models.Printer, error) {
+	printer, err := printerFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	printer.CreatedBy = &userID
+
+	return s.repos.Printer.CreatePrinter(ctx, printer)
+}
+
+// UpdatePrinter replaces a printer's details on behalf of userID. Its print width is kept
+// when the request leaves chars_per_line out.
+func (s *PrinterService) UpdatePrinter(ctx context.Context, id, userID uuid.UUID, req models.PrinterRequest) (*models.Printer, error) {
+	printer, err := printerFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CharsPerLine == nil {
+		existing, err := s.repos.Printer.GetPrinterByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		printer.CharsPerLine = existing.CharsPerLine
+	}
+	printer.ID = id
+	printer.UpdatedBy = &userID
+
+	return s.repos.Printer.UpdatePrinter(ctx, printer)
+}
+
+// printerFromRequest validates a printer request. is_default is shorthand for default_for
+// receipt, so the two can't name different purposes.
+func printerFromRequest(req models.PrinterRequest) (models.Printer, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return models.Printer{}, fmt.Errorf("%w: name must be 1 to 100 characters", ErrInvalidPrinter)
+	}
+	if !req.Type.IsValid() {
+		return models.Printer{}, fmt.Errorf("%w: type must be one of: thermal, kitchen, receipt, other", ErrInvalidPrinter)
+	}
+	if req.Port != nil && (*req.Port < 1 || *req.Port > 65535) {
+		return models.Printer{}, fmt.Errorf("%w: port must be between 1 and 65535", ErrInvalidPrinter)
+	}
+	if req.CharsPerLine != nil && (*req.CharsPerLine < models.MinPrinterCharsPerLine || *req.CharsPerLine > models.MaxPrinterCharsPerLine) {
+		return models.Printer{}, fmt.Errorf("%w: chars_per_line must be between %d and %d",
+			ErrInvalidPrinter, models.MinPrinterCharsPerLine, models.MaxPrinterCharsPerLine)
+	}
+	if req.DefaultFor != nil {
+		if !req.DefaultFor.IsValid() {
+			return models.Printer{}, fmt.Errorf("%w: %q", ErrInvalidPrinterPurpose, *req.DefaultFor)
+		}
+		if req.IsDefault && *req.DefaultFor != models.PrinterPurposeReceipt {
+			return models.Printer{}, fmt.Errorf("%w: is_default means default_for receipt", ErrInvalidPrinter)
+		}
+	}
+
+	printer := models.Printer{
+		Name:       name,
+		Type:       req.Type,
+		IPAddress:  req.IPAddress,
+		Port:       req.Port,
+		Model:      req.Model,
+		IsDefault:  req.IsDefault,
+		DefaultFor: req.DefaultFor,
+		IsActive:   req.IsActive,
+	}
+	if req.CharsPerLine != nil {
+		printer.CharsPerLine = *req.CharsPerLine
+	}
+
+	return printer, nil
+}
+
+// SetDefaultPrinter makes the given printer the only default for purpose on behalf of userID.
+// An empty purpose means receipts.
+func (s *PrinterService) SetDefaultPrinter(ctx context.Context, id, userID uuid.UUID, purpose models.PrinterPurpose) (*models.Printer, error) {
+	if purpose == "" {
+		purpose = models.PrinterPurposeReceipt
+	}
+	if !purpose.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPrinterPurpose, purpose)
+	}
+
+	return s.repos.Printer.SetDefaultPrinter(ctx, id, userID, purpose)
+}
+
+// GetDefaultPrinter returns the active default printer for purpose
+func (s *PrinterService) GetDefaultPrinter(ctx context.Context, purpose models.PrinterPurpose) (*models.Printer, error) {
+	if !purpose.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPrinterPurpose, purpose)
+	}
+
+	return s.repos.Printer.GetDefaultPrinter(ctx, purpose)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+func TestPrinterFromRequest(t *testing.T) {
+	purpose := func(p models.PrinterPurpose) *models.PrinterPurpose { return &p }
+	intPtr := func(p int) *int { return &p }
+
+	tests := []struct {
+		name string
+		req  models.PrinterRequest
+		want error
+	}{
+		{"kitchen default", models.PrinterRequest{Name: "Pass", Type: models.PrinterTypeKitchen, DefaultFor: purpose(models.PrinterPurposeKitchen)}, nil},
+		{"receipt shorthand", models.PrinterRequest{Name: "Front", Type: models.PrinterTypeReceipt, IsDefault: true}, nil},
+		{"shorthand agrees", models.PrinterRequest{Name: "Front", Type: models.PrinterTypeReceipt, IsDefault: true, DefaultFor: purpose(models.PrinterPurposeReceipt)}, nil},
+		{"shorthand disagrees", models.PrinterRequest{Name: "Front", Type: models.PrinterTypeReceipt, IsDefault: true, DefaultFor: purpose(models.PrinterPurposeLabel)}, ErrInvalidPrinter},
+		{"unknown purpose", models.PrinterRequest{Name: "Pass", Type: models.PrinterTypeKitchen, DefaultFor: purpose("menu")}, ErrInvalidPrinterPurpose},
+		{"blank name", models.PrinterRequest{Name: " ", Type: models.PrinterTypeKitchen}, ErrInvalidPrinter},
+		{"unknown type", models.PrinterRequest{Name: "Pass", Type: "laser"}, ErrInvalidPrinter},
+		{"port out of range", models.PrinterRequest{Name: "Pass", Type: models.PrinterTypeKitchen, Port: intPtr(70000)}, ErrInvalidPrinter},
+		{"narrowest width", models.PrinterRequest{Name: "Pass", Type: models.PrinterTypeKitchen, CharsPerLine: intPtr(24)}, nil},
+		{"widest width", models.PrinterRequest{Name: "Pass", Type: models.PrinterTypeKitchen, CharsPerLine: intPtr(80)}, nil},
+		{"width too narrow", models.PrinterRequest{Name: "Pass", Type: models.PrinterTypeKitchen, CharsPerLine: intPtr(23)}, ErrInvalidPrinter},
+		{"width too wide", models.PrinterRequest{Name: "Pass", Type: models.PrinterTypeKitchen, CharsPerLine: intPtr(81)}, ErrInvalidPrinter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer, err := printerFromRequest(tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			if printer.DefaultFor != tt.req.DefaultFor || printer.IsDefault != tt.req.IsDefault {
+				t.Errorf("default = %v/%v, want %v/%v", printer.DefaultFor, printer.IsDefault, tt.req.DefaultFor, tt.req.IsDefault)
+			}
+			if tt.req.CharsPerLine != nil && printer.CharsPerLine != *tt.req.CharsPerLine {
+				t.Errorf("chars per line = %d, want %d", printer.CharsPerLine, *tt.req.CharsPerLine)
+			}
+		})
+	}
+}
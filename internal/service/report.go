@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+)
+
+// ReportService handles reporting business logic
+type ReportService struct {
+	repos *repository.Repositories
+}
+
+// NewReportService creates a new report service
+func NewReportService(repos *repository.Repositories) *ReportService {
+	return &ReportService{
+		repos: repos,
+	}
+}
+
+// GetWaste reports the items voided between two business dates, both inclusive, split into
+// pre-fire cancels and post-fire waste
+func (s *ReportService) GetWaste(ctx context.Context, startDate, endDate time.Time) (*models.WasteReport, error) {
+	if endDate.Before(startDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	items, err := s.repos.Order.GetVoidedItems(ctx, BusinessDayStart(startDate), BusinessDayStart(endDate.AddDate(0, 0, 1)))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.WasteReport{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Orders:    []models.OrderWaste{},
+		Items:     []models.VoidedItem{},
+	}
+	for _, item := range items {
+		report.Add(item)
+	}
+
+	return report, nil
+}
+
+// ExportOrders calls fn for every order of a business day, oldest first, streaming them from
+// the database for exports that shouldn't hold the whole day in memory
+func (s *ReportService) ExportOrders(ctx context.Context, date time.Time, fn func(models.Order) error) error {
+	return s.repos.Order.EachOrder(ctx, BusinessDayStart(date), BusinessDayStart(date.AddDate(0, 0, 1)), fn)
+}
+
+// GetPrepTimes reports item prep-time percentiles between two business dates, both inclusive
+func (s *ReportService) GetPrepTimes(ctx context.Context, startDate, endDate time.Time) (*models.PrepTimeReport, error) {
+	if endDate.Before(startDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	stats, err := s.repos.Order.GetPrepTimeStats(ctx, BusinessDayStart(startDate), BusinessDayStart(endDate.AddDate(0, 0, 1)))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PrepTimeReport{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Stations:  []models.PrepTimeStats{},
+	}
+	for _, stat := range stats {
+		if stat.StationID == nil {
+			report.Overall = stat
+			continue
+		}
+		report.Stations = append(report.Stations, stat)
+	}
+
+	return report, nil
+}
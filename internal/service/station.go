@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"github.com/pizza-nz/restaurant-service/internal/websockets"
+)
+
+var (
+	// ErrInvalidStationAction is returned for an unknown station action
+	ErrInvalidStationAction = errors.New("invalid station action")
+
+	// ErrNoStationItems is returned when a station has no item the action can apply to
+	ErrNoStationItems = errors.New("no items to act on")
+
+	// ErrItemNotAtStation is returned when an action names an item routed to another station
+	ErrItemNotAtStation = errors.New("item is not at this station")
+
+	// ErrInvalidItemTransition is returned when an item's status doesn't allow the action
+	ErrInvalidItemTransition = errors.New("action not allowed for item status")
+
+	// ErrInvalidRoutingRule is returned for a routing rule with a priority below 1
+	ErrInvalidRoutingRule = errors.New("priority must be at least 1")
+
+	// ErrTooManyStations is returned when a combined feed names more than maxFeedStations stations
+	ErrTooManyStations = fmt.Errorf("at most %d stations can be combined", maxFeedStations)
+
+	// ErrSameStation is returned when routing is reassigned to the station it comes from
+	ErrSameStation = errors.New("target station must be a different station")
+
+	// ErrInvalidPrinterAssignment is returned for an unknown or repeated printer role
+	ErrInvalidPrinterAssignment = errors.New("invalid printer assignment")
+)
+
+// maxFeedStations caps how many stations one combined feed can cover
+const maxFeedStations = 10
+
+// bumpStatus maps each status a bump can advance to the status it moves to
+var bumpStatus = map[models.OrderItemStatus]models.OrderItemStatus{
+	models.OrderItemStatusPending:    models.OrderItemStatusInProgress,
+	models.OrderItemStatusInProgress: models.OrderItemStatusReady,
+	models.OrderItemStatusReady:      models.OrderItemStatusCompleted,
+}
+
+// StationService handles station-related business logic
+type StationService struct {
+	repos *repository.Repositories
+	hub   websockets.Broadcaster
+}
+
+// NewStationService creates a new station service
+func NewStationService(repos *repository.Repositories, hub websockets.Broadcaster) *StationService {
+	if hub == nil {
+		hub = websockets.Discard
+	}
+
+	return &StationService{
+		repos: repos,
+		hub:   hub,
+	}
+}
+
+// GetStationItems retrieves the pending and in-progress items for a station
+func (s *StationService) GetStationItems(ctx context.Context, stationID uuid.UUID, groupBy models.StationItemsGroupBy) ([]models.OrderItem, error) {
+	switch groupBy {
+	case "":
+		groupBy = models.StationItemsGroupByTime
+	case models.StationItemsGroupByTime, models.StationItemsGroupByOrder:
+	default:
+		return nil, fmt.Errorf("invalid grouping: %s", groupBy)
+	}
+
+	return s.repos.Order.GetStationItems(ctx, stationID, groupBy)
+}
+
+// GetItemsForStations retrieves one combined feed for several stations. Every station
+// must exist; repeated IDs are ignored.
+func (s *StationService) GetItemsForStations(ctx context.Context, stationIDs []uuid.UUID, groupBy models.StationItemsGroupBy) ([]models.OrderItem, error) {
+	switch groupBy {
+	case "":
+		groupBy = models.StationItemsGroupByTime
+	case models.StationItemsGroupByTime, models.StationItemsGroupByOrder:
+	default:
+		return nil, fmt.Errorf("invalid grouping: %s", groupBy)
+	}
+
+	ids := make([]uuid.UUID, 0, len(stationIDs))
+	for _, id := range stationIDs {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxFeedStations {
+		return nil, ErrTooManyStations
+	}
+
+	for _, id := range ids {
+		_, err := s.repos.Station.GetByID(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("station %s %w", id, repository.ErrNotFound)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repos.Order.GetItemsForStations(ctx, ids, groupBy)
+}
+
+// GetRoutedMenuItems lists the menu items routed to a station, so they can be moved before it is deleted
+func (s *StationService) GetRoutedMenuItems(ctx context.Context, stationID uuid.UUID) ([]models.StationMenuItem, error) {
+	if _, err := s.repos.Station.GetByID(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Station.GetRoutedMenuItems(ctx, stationID)
+}
+
+// AssignCategoryRouting routes every item in a category to an active station
+func (s *StationService) AssignCategoryRouting(ctx context.Context, req models.RoutingBulkRequest) (*models.RoutingBulkResult, error) {
+	if req.Priority == 0 {
+		req.Priority = 1
+	}
+	if req.Priority < 1 {
+		return nil, ErrInvalidRoutingRule
+	}
+
+	if _, err := s.repos.Menu.GetCategoryByID(ctx, req.CategoryID); err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
+	}
+
+	station, err := s.repos.Station.GetByID(ctx, req.StationID)
+	if err != nil {
+		return nil, fmt.Errorf("station not found: %w", err)
+	}
+	if !station.IsActive {
+		return nil, ErrStationInactive
+	}
+
+	assigned, skipped, err := s.repos.Station.AssignCategoryRouting(ctx, req.CategoryID, req.StationID, req.Priority, req.Replace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RoutingBulkResult{
+		CategoryID: req.CategoryID,
+		StationID:  req.StationID,
+		Priority:   req.Priority,
+		Assigned:   assigned,
+		Skipped:    skipped,
+	}, nil
+}
+
+// ReassignRouting moves every routing rule of a station, and optionally its open items, to an
+// active target station so the station can then be deleted
+func (s *StationService) ReassignRouting(ctx context.Context, stationID uuid.UUID, req models.RoutingReassignRequest) (*models.RoutingReassignResult, error) {
+	if req.TargetStationID == stationID {
+		return nil, ErrSameStation
+	}
+
+	if _, err := s.repos.Station.GetByID(ctx, stationID); err != nil {
+		return nil, fmt.Errorf("station not found: %w", err)
+	}
+
+	target, err := s.repos.Station.GetByID(ctx, req.TargetStationID)
+	if err != nil {
+		return nil, fmt.Errorf("target station not found: %w", err)
+	}
+	if !target.IsActive {
+		return nil, ErrStationInactive
+	}
+
+	return s.repos.Station.ReassignRouting(ctx, stationID, req.TargetStationID, req.MoveOpenItems)
+}
+
+// SetStationPrinters replaces a station's printers, one per role, on behalf of userID
+func (s *StationService) SetStationPrinters(ctx context.Context, stationID, userID uuid.UUID, req models.StationPrintersRequest) ([]models.StationPrinter, error) {
+	seen := make(map[models.PrinterRole]bool, len(req.Printers))
+	for _, a := range req.Printers {
+		if !a.Role.IsValid() {
+			return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidPrinterAssignment, a.Role)
+		}
+		if seen[a.Role] {
+			return nil, fmt.Errorf("%w: role %q assigned more than once", ErrInvalidPrinterAssignment, a.Role)
+		}
+		seen[a.Role] = true
+
+		printer, err := s.repos.Printer.GetPrinterByID(ctx, a.PrinterID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: printer %s not found", ErrInvalidPrinterAssignment, a.PrinterID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !printer.IsActive {
+			return nil, fmt.Errorf("%w: printer %s is inactive", ErrInvalidPrinterAssignment, printer.Name)
+		}
+	}
+
+	if err := s.repos.Station.SetStationPrinters(ctx, stationID, userID, req.Printers); err != nil {
+		return nil, err
+	}
+
+	return s.repos.Station.GetStationPrinters(ctx, stationID)
+}
+
+// GetStationPrinters returns a station's printer assignments
+func (s *StationService) GetStationPrinters(ctx context.Context, stationID uuid.UUID) ([]models.StationPrinter, error) {
+	return s.repos.Station.GetStationPrinters(ctx, stationID)
+}
+
+// GetPrinterForRole returns the printer a station sends the given kind of output to
+func (s *StationService) GetPrinterForRole(ctx context.Context, stationID uuid.UUID, role models.PrinterRole) (*models.Printer, error) {
+	return s.repos.Station.GetPrinterForRole(ctx, stationID, role)
+}
+
+// PerformAction applies a bump, recall or next action to a station's items and
+// broadcasts the resulting item update
+func (s *StationService) PerformAction(ctx context.Context, stationID uuid.UUID, req models.StationActionRequest) (*models.OrderItem, error) {
+	var item *models.OrderItem
+	var err error
+
+	switch req.Action {
+	case models.StationActionBump:
+		item, err = s.actionItem(ctx, stationID, req.ItemID, func(i models.OrderItem) bool {
+			_, ok := bumpStatus[i.Status]
+			return ok
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = s.repos.Order.UpdateItemStatus(ctx, item.ID, item.Status, bumpStatus[item.Status])
+
+	case models.StationActionNext:
+		item, err = s.actionItem(ctx, stationID, req.ItemID, func(i models.OrderItem) bool {
+			return i.Status == models.OrderItemStatusPending
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = s.repos.Order.UpdateItemStatus(ctx, item.ID, item.Status, models.OrderItemStatusInProgress)
+
+	case models.StationActionRecall:
+		if req.ItemID == nil {
+			item, err = s.repos.Order.GetLastCompletedStationItem(ctx, stationID)
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrNoStationItems
+			}
+		} else {
+			item, err = s.stationItem(ctx, stationID, *req.ItemID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if item.Status != models.OrderItemStatusReady && item.Status != models.OrderItemStatusCompleted {
+			return nil, ErrInvalidItemTransition
+		}
+		err = s.repos.Order.RecallItem(ctx, item.ID)
+
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStationAction, req.Action)
+	}
+	if errors.Is(err, repository.ErrItemStatusChanged) {
+		return nil, ErrInvalidItemTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repos.Order.GetOrderItem(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"action": req.Action,
+		"item":   updated,
+	}
+	if err := s.hub.BroadcastMessage(websockets.TypeItemUpdate, data); err != nil {
+		log.Printf("Failed to broadcast item update: %v", err)
+	}
+
+	return updated, nil
+}
+
+// ReorderItems puts the given items of a station's feed first, in the given order, and
+// broadcasts the new feed. Every item must currently be in the feed.
+func (s *StationService) ReorderItems(ctx context.Context, stationID uuid.UUID, req models.StationReorderRequest) ([]models.OrderItem, error) {
+	if _, err := s.repos.Station.GetByID(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	feed, err := s.repos.Order.GetStationItems(ctx, stationID, models.StationItemsGroupByTime)
+	if err != nil {
+		return nil, err
+	}
+
+	inFeed := make(map[uuid.UUID]bool, len(feed))
+	for _, item := range feed {
+		inFeed[item.ID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(req.ItemIDs))
+	for _, id := range req.ItemIDs {
+		if !inFeed[id] {
+			return nil, fmt.Errorf("%w: %s", ErrItemNotAtStation, id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: item %s is listed more than once", ErrInvalidStationAction, id)
+		}
+		seen[id] = true
+	}
+
+	if err := s.repos.Order.SetDisplayPriorities(ctx, stationID, req.ItemIDs); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repos.Order.GetStationItems(ctx, stationID, models.StationItemsGroupByTime)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"station_id": stationID,
+		"items":      items,
+	}
+	if err := s.hub.BroadcastMessage(websockets.TypeStationItems, data); err != nil {
+		log.Printf("Failed to broadcast station items: %v", err)
+	}
+
+	return items, nil
+}
+
+// HandleSocketAction runs a station.action message sent by a KDS client registered to stationID
+func (s *StationService) HandleSocketAction(stationID string, data json.RawMessage) (interface{}, error) {
+	id, err := uuid.Parse(stationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid station ID: %w", err)
+	}
+
+	var req models.StationActionRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStationAction, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.PerformAction(ctx, id, req)
+}
+
+// actionItem returns the named item, or the oldest item in the station feed that accepts the action
+func (s *StationService) actionItem(ctx context.Context, stationID uuid.UUID, itemID *uuid.UUID, accepts func(models.OrderItem) bool) (*models.OrderItem, error) {
+	if itemID != nil {
+		item, err := s.stationItem(ctx, stationID, *itemID)
+		if err != nil {
+			return nil, err
+		}
+		if !accepts(*item) {
+			return nil, ErrInvalidItemTransition
+		}
+		return item, nil
+	}
+
+	items, err := s.repos.Order.GetStationItems(ctx, stationID, models.StationItemsGroupByTime)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if accepts(items[i]) {
+			return &items[i], nil
+		}
+	}
+
+	return nil, ErrNoStationItems
+}
+
+// stationItem loads an item and checks it is routed to stationID
+func (s *StationService) stationItem(ctx context.Context, stationID, itemID uuid.UUID) (*models.OrderItem, error) {
+	item, err := s.repos.Order.GetOrderItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.StationID != stationID {
+		return nil, ErrItemNotAtStation
+	}
+
+	return item, nil
+}
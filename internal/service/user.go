@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pizza-nz/restaurant-service/internal/db/repository"
+	"github.com/pizza-nz/restaurant-service/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidDateRange is returned when a range ends before it starts
+	ErrInvalidDateRange = errors.New("end date is before start date")
+
+	// ErrInvalidUserImport is returned when an import file can't be read as a roster
+	ErrInvalidUserImport = errors.New("invalid user import")
+)
+
+// userImportColumns are the columns a user import file must have, in any order
+var userImportColumns = []string{"username", "name", "role", "temporary_password"}
+
+// UserService handles user-related business logic
+type UserService struct {
+	repos *repository.Repositories
+}
+
+// NewUserService creates a new user service
+func NewUserService(repos *repository.Repositories) *UserService {
+	return &UserService{
+		repos: repos,
+	}
+}
+
+// GetUserStats summarises a user's orders between two business dates, both inclusive
+func (s *UserService) GetUserStats(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (*models.UserStats, error) {
+	if endDate.Before(startDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if _, err := s.repos.User.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	stats, err := s.repos.Order.GetUserStats(ctx, userID, BusinessDayStart(startDate), BusinessDayStart(endDate.AddDate(0, 0, 1)))
+	if err != nil {
+		return nil, err
+	}
+
+	stats.UserID = userID
+	stats.StartDate = startDate.Format("2006-01-02")
+	stats.EndDate = endDate.Format("2006-01-02")
+
+	return stats, nil
+}
+
+// ImportUsers creates a user for each row of a CSV roster with the columns in
+// userImportColumns. Every account gets a bcrypt-hashed temporary password it
+// must change on first login. Rows that fail are reported without stopping the rest.
+func (s *UserService) ImportUsers(ctx context.Context, roster io.Reader) (*models.UserImportResult, error) {
+	reader := csv.NewReader(roster)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read header: %w", ErrInvalidUserImport, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range userImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidUserImport, name)
+		}
+	}
+	reader.FieldsPerRecord = len(header)
+
+	result := &models.UserImportResult{Rows: []models.UserImportRowResult{}}
+	seen := make(map[string]bool)
+
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidUserImport, err)
+			}
+			result.Failed++
+			result.Rows = append(result.Rows, models.UserImportRowResult{Row: row, Error: parseErr.Err.Error()})
+			continue
+		}
+
+		field := func(name string) string {
+			return strings.TrimSpace(record[columns[name]])
+		}
+
+		rowResult := models.UserImportRowResult{Row: row, Username: field("username")}
+
+		user, err := s.importUser(ctx, field("username"), field("name"), models.UserRole(strings.ToLower(field("role"))), field("temporary_password"), seen)
+		if err != nil {
+			rowResult.Error = err.Error()
+			result.Failed++
+		} else {
+			rowResult.User = user
+			result.Created++
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	return result, nil
+}
+
+// importUser validates and creates one imported user, seen holds the usernames already in the file
+func (s *UserService) importUser(ctx context.Context, username, name string, role models.UserRole, password string, seen map[string]bool) (*models.User, error) {
+	switch {
+	case len(username) < 3 || len(username) > 50:
+		return nil, errors.New("username must be between 3 and 50 characters")
+	case len(name) < 2 || len(name) > 100:
+		return nil, errors.New("name must be between 2 and 100 characters")
+	case !role.IsValid():
+		return nil, fmt.Errorf("invalid role %q", role)
+	case len(password) < 6:
+		return nil, errors.New("temporary password must be at least 6 characters")
+	}
+
+	if seen[username] {
+		return nil, errors.New("duplicate username in file")
+	}
+	seen[username] = true
+
+	if _, err := s.repos.User.GetByUsername(ctx, username); err == nil {
+		return nil, repository.ErrDuplicateUsername
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user, err := s.repos.User.Create(ctx, models.User{
+		Username:           username,
+		PasswordHash:       string(hashedPassword),
+		Name:               name,
+		Role:               role,
+		IsActive:           true,
+		MustChangePassword: true,
+	})
+	if errors.Is(err, repository.ErrDuplicateUsername) {
+		return nil, repository.ErrDuplicateUsername
+	}
+	if err != nil {
+		log.Printf("Failed to import user %s: %v", username, err)
+		return nil, errors.New("failed to create user")
+	}
+
+	return user, nil
+}
@@ -3,6 +3,7 @@ package websockets
 import (
 	"encoding/json"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -16,21 +17,30 @@ const (
 	pingPeriod = (pongWait * 9) / 10
 
 	maxMessageSize = 1024 * 1024 // 1MB
+
+	// maxQueuedJobs is how much blocking work one client may have waiting, see Client.dispatch
+	maxQueuedJobs = 16
 )
 
 type MessageType string
 
 const (
-	TypeOrderNew        MessageType = "order.new"
-	TypeOrderUpdate     MessageType = "order.update"
-	TypeItemUpdate      MessageType = "item.update"
-	TypeMenuUpdate      MessageType = "menu.update"
-	TypeStationItems    MessageType = "station.items"
-	TypeDisplayRegister MessageType = "display.register"
-	TypePrinterStatus   MessageType = "printer.status"
-	TypeError           MessageType = "error"
-	TypePing            MessageType = "ping"
-	TypePong            MessageType = "pong"
+	TypeOrderNew         MessageType = "order.new"
+	TypeOrderUpdate      MessageType = "order.update"
+	TypeItemUpdate       MessageType = "item.update"
+	TypeMenuUpdate       MessageType = "menu.update"
+	TypeItemAvailability MessageType = "item.availability"
+	TypeRoutingUpdate    MessageType = "routing.update"
+	TypeStationItems     MessageType = "station.items"
+	TypeDisplayRegister  MessageType = "display.register"
+	TypeDisplayOffline   MessageType = "display.offline"
+	TypePrinterStatus    MessageType = "printer.status"
+	TypeStationAction    MessageType = "station.action"
+	TypeStationResult    MessageType = "station.action.result"
+	TypeError            MessageType = "error"
+	TypePing             MessageType = "ping"
+	TypePong             MessageType = "pong"
+	TypeServerTime       MessageType = "server.time"
 )
 
 type ClientType string
@@ -43,6 +53,27 @@ const (
 	ClientTypePrinter ClientType = "printer"
 )
 
+// PingData is the optional payload of an application-level ping
+type PingData struct {
+	ClientTime *int64 `json:"client_time,omitempty"` // Unix milliseconds on the client when the ping was sent
+}
+
+// PongData answers an application-level ping with the connection's health
+type PongData struct {
+	ClientTime          *int64 `json:"client_time,omitempty"` // Echoed from the ping so the client can time the round trip
+	ServerTime          int64  `json:"server_time"`           // Unix milliseconds
+	LatencyMs           *int64 `json:"latency_ms,omitempty"`  // Last protocol-level ping round trip, once measured
+	HeartbeatIntervalMs int64  `json:"heartbeat_interval_ms"` // How often the server pings the connection
+	PongTimeoutMs       int64  `json:"pong_timeout_ms"`       // Silence after which the server drops the connection
+}
+
+// ServerTimeData is sent on connect and with every heartbeat so clients can
+// detect clock skew and notice when pushes stop arriving
+type ServerTimeData struct {
+	ServerTime          int64 `json:"server_time"` // Unix milliseconds
+	HeartbeatIntervalMs int64 `json:"heartbeat_interval_ms"`
+}
+
 type Message struct {
 	Type      MessageType     `json:"type"`
 	Data      json.RawMessage `json:"data"`
@@ -54,11 +85,23 @@ type Client struct {
 	conn *websocket.Conn
 	send chan []byte
 
+	// jobs runs work that may block, such as database calls, off the read loop. workDone
+	// is closed once workPump has run everything queued.
+	jobs     chan func()
+	workDone chan struct{}
+
 	userID string
 
 	clientType ClientType
 
 	stationID string
+
+	displayID string
+
+	// pingSentAt is when the last protocol ping went out (unix nanoseconds), latency the
+	// round trip of the last one answered. Written by writePump and the pong handler.
+	pingSentAt atomic.Int64
+	latency    atomic.Int64
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, userID string, clientType ClientType) *Client {
@@ -66,6 +109,8 @@ func NewClient(hub *Hub, conn *websocket.Conn, userID string, clientType ClientT
 		hub:        hub,
 		conn:       conn,
 		send:       make(chan []byte, 256),
+		jobs:       make(chan func(), maxQueuedJobs),
+		workDone:   make(chan struct{}),
 		userID:     userID,
 		clientType: clientType,
 	}
@@ -78,8 +123,67 @@ func (c *Client) SetStationID(stationID string) {
 	}
 }
 
+// sendMessage queues a typed message for this client only. A client too slow to drain its
+// queue is disconnected instead of blocking the caller.
+func (c *Client) sendMessage(msgType MessageType, data interface{}) {
+	message, err := newMessage(msgType, data)
+	if err != nil {
+		log.Printf("Error marshaling %s message: %v", msgType, err)
+		return
+	}
+
+	select {
+	case c.send <- message:
+	default:
+		log.Printf("Dropping %s message and closing slow client %s", msgType, c.userID)
+		c.conn.Close()
+	}
+}
+
+// dispatch queues job to run on the client's worker, so the read loop keeps reading and
+// answering pings while it waits on the database. It returns false, dropping the job, when
+// too much work is already queued.
+func (c *Client) dispatch(job func()) bool {
+	select {
+	case c.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// workPump runs dispatched jobs one at a time, in the order they were read
+func (c *Client) workPump() {
+	defer close(c.workDone)
+
+	for job := range c.jobs {
+		job()
+	}
+}
+
+// newMessage encodes data as a typed message
+func newMessage(msgType MessageType, data interface{}) ([]byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Message{Type: msgType, Data: payload})
+}
+
+// heartbeat reports liveness for clients registered as a display. A heartbeat dropped
+// because the worker is busy is covered by the next one.
+func (c *Client) heartbeat() {
+	if displayID := c.displayID; displayID != "" {
+		c.dispatch(func() { c.hub.displayHeartbeat(displayID) })
+	}
+}
+
 func (c *Client) readPump() {
 	defer func() {
+		// Let queued jobs finish before the hub closes the send channel they reply on
+		close(c.jobs)
+		<-c.workDone
 		c.hub.unregister <- c
 		c.conn.Close()
 	}()
@@ -88,6 +192,10 @@ func (c *Client) readPump() {
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		if sent := c.pingSentAt.Load(); sent != 0 {
+			c.latency.Store(time.Now().UnixNano() - sent)
+		}
+		c.heartbeat()
 		return nil
 	})
 
@@ -112,12 +220,15 @@ func (c *Client) readPump() {
 		case TypeDisplayRegister:
 			var registerData struct {
 				StationID string `json:"station_id"`
+				DisplayID string `json:"display_id,omitempty"`
 			}
 			if err := json.Unmarshal(wsMessage.Data, &registerData); err != nil {
 				log.Printf("Error unmarshaling register data: %v", err)
 				continue
 			}
 			c.SetStationID(registerData.StationID)
+			c.displayID = registerData.DisplayID
+			c.heartbeat()
 
 		case TypePrinterStatus:
 			// Handle printer
@@ -133,9 +244,37 @@ func (c *Client) readPump() {
 			statusMsg, _ := json.Marshal(wsMessage)
 			c.hub.broadcast <- statusMsg
 
+		case TypeStationAction:
+			// Bump bars drive item status over the socket, so they must be bound to a station
+			if c.clientType != ClientTypeKDS || c.stationID == "" {
+				c.sendMessage(TypeError, map[string]string{
+					"error": "station.action requires a kds client registered to a station",
+				})
+				continue
+			}
+
+			stationID, data := c.stationID, wsMessage.Data
+			if !c.dispatch(func() { c.runStationAction(stationID, data) }) {
+				c.sendMessage(TypeStationResult, map[string]interface{}{
+					"success": false,
+					"error":   "too many station actions in progress, try again",
+				})
+			}
+
 		case TypePing:
-			pongMsg, _ := json.Marshal(Message{Type: TypePong})
-			c.send <- pongMsg
+			c.heartbeat()
+
+			// The payload is optional, older clients send a bare ping
+			var ping PingData
+			if len(wsMessage.Data) > 0 {
+				if err := json.Unmarshal(wsMessage.Data, &ping); err != nil {
+					log.Printf("Error unmarshaling ping data: %v", err)
+				}
+			}
+			c.sendMessage(TypePong, c.pong(ping))
+
+		case TypeServerTime:
+			c.sendMessage(TypeServerTime, serverTime())
 
 		default:
 			// For other messages, just broadcast to all clients
@@ -145,6 +284,45 @@ func (c *Client) readPump() {
 	}
 }
 
+// runStationAction performs a station.action on the client's worker and replies with the result
+func (c *Client) runStationAction(stationID string, data json.RawMessage) {
+	result, err := c.hub.stationAction(stationID, data)
+	if err != nil {
+		c.sendMessage(TypeStationResult, map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.sendMessage(TypeStationResult, map[string]interface{}{
+		"success": true,
+		"item":    result,
+	})
+}
+
+// pong builds the reply to an application-level ping
+func (c *Client) pong(ping PingData) PongData {
+	pong := PongData{
+		ClientTime:          ping.ClientTime,
+		ServerTime:          time.Now().UnixMilli(),
+		HeartbeatIntervalMs: pingPeriod.Milliseconds(),
+		PongTimeoutMs:       pongWait.Milliseconds(),
+	}
+	if latency := c.latency.Load(); latency != 0 {
+		ms := time.Duration(latency).Milliseconds()
+		pong.LatencyMs = &ms
+	}
+	return pong
+}
+
+// serverTime returns the current server.time payload
+func serverTime() ServerTimeData {
+	return ServerTimeData{
+		ServerTime:          time.Now().UnixMilli(),
+		HeartbeatIntervalMs: pingPeriod.Milliseconds(),
+	}
+}
+
 func (c *Client) writePump() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
@@ -179,9 +357,20 @@ func (c *Client) writePump() {
 
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.pingSentAt.Store(time.Now().UnixNano())
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
+
+			// Protocol pings are invisible to browser code, so send the time alongside
+			timeMsg, err := newMessage(TypeServerTime, serverTime())
+			if err != nil {
+				log.Printf("Error marshaling %s message: %v", TypeServerTime, err)
+				continue
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, timeMsg); err != nil {
+				return
+			}
 		}
 	}
 }
@@ -191,6 +380,10 @@ func ServeWs(hub *Hub, conn *websocket.Conn, userID string, clientType ClientTyp
 
 	client.hub.register <- client
 
+	// Give the client a clock reference straight away
+	client.sendMessage(TypeServerTime, serverTime())
+
 	go client.writePump()
+	go client.workPump()
 	go client.readPump()
 }
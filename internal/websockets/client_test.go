@@ -0,0 +1,31 @@
+package websockets
+
+import "testing"
+
+func TestClientDispatch(t *testing.T) {
+	c := &Client{
+		jobs:     make(chan func(), maxQueuedJobs),
+		workDone: make(chan struct{}),
+	}
+
+	ran := 0
+	for i := 0; i < maxQueuedJobs; i++ {
+		if !c.dispatch(func() { ran++ }) {
+			t.Fatalf("dispatch %d rejected with room in the queue", i)
+		}
+	}
+	if c.dispatch(func() { ran++ }) {
+		t.Fatal("dispatch accepted a job with the queue full")
+	}
+
+	close(c.jobs)
+	c.workPump()
+	if ran != maxQueuedJobs {
+		t.Errorf("ran %d jobs, want %d", ran, maxQueuedJobs)
+	}
+	select {
+	case <-c.workDone:
+	default:
+		t.Error("workDone not closed after the queue drained")
+	}
+}
@@ -1,6 +1,9 @@
 package websockets
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"sync"
 )
 
@@ -15,6 +18,14 @@ type Hub struct {
 
 	stationChannels map[string]map[*Client]bool
 
+	onDisplayHeartbeat func(displayID string)
+
+	onStationAction func(stationID string, data json.RawMessage) (interface{}, error)
+
+	// ready is closed once Run is processing registrations
+	ready     chan struct{}
+	readyOnce sync.Once
+
 	mu sync.Mutex
 }
 
@@ -25,7 +36,43 @@ func NewHub() *Hub {
 		unregister:      make(chan *Client),
 		clients:         make(map[*Client]bool),
 		stationChannels: make(map[string]map[*Client]bool),
+		ready:           make(chan struct{}),
+	}
+}
+
+// Ready reports whether Run has started, before then connections can't be registered
+func (h *Hub) Ready() bool {
+	select {
+	case <-h.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetDisplayHeartbeatHandler sets the callback invoked whenever a display reports liveness.
+// It must be set before clients connect.
+func (h *Hub) SetDisplayHeartbeatHandler(fn func(displayID string)) {
+	h.onDisplayHeartbeat = fn
+}
+
+func (h *Hub) displayHeartbeat(displayID string) {
+	if h.onDisplayHeartbeat != nil {
+		h.onDisplayHeartbeat(displayID)
+	}
+}
+
+// SetStationActionHandler sets the callback that runs station.action messages from KDS clients.
+// It must be set before clients connect.
+func (h *Hub) SetStationActionHandler(fn func(stationID string, data json.RawMessage) (interface{}, error)) {
+	h.onStationAction = fn
+}
+
+func (h *Hub) stationAction(stationID string, data json.RawMessage) (interface{}, error) {
+	if h.onStationAction == nil {
+		return nil, errors.New("station actions are not available")
 	}
+	return h.onStationAction(stationID, data)
 }
 
 func (h *Hub) RegisterStationClient(client *Client, stationID string) {
@@ -47,15 +94,50 @@ func (h *Hub) BroadcastToStation(stationID string, message []byte) {
 			select {
 			case client.send <- message:
 			default:
-				close(client.send)
-				delete(clients, client)
-				delete(h.clients, client)
+				// Closing the connection unregisters the client, which closes its send channel
+				client.conn.Close()
 			}
 		}
 	}
 }
 
+// Broadcaster sends typed messages to connected clients. Handlers and services depend on it
+// rather than on *Hub so they can be built with a fake, or without WebSockets at all.
+type Broadcaster interface {
+	BroadcastMessage(msgType MessageType, data interface{}) error
+}
+
+// Discard is a Broadcaster that drops every message, used in place of a nil broadcaster
+var Discard Broadcaster = discard{}
+
+type discard struct{}
+
+func (discard) BroadcastMessage(MessageType, interface{}) error { return nil }
+
+// BroadcastMessage wraps data in a typed message and sends it to all clients. A nil hub
+// drops the message.
+func (h *Hub) BroadcastMessage(msgType MessageType, data interface{}) error {
+	if h == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message data: %w", err)
+	}
+
+	message, err := json.Marshal(Message{Type: msgType, Data: payload})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	h.broadcast <- message
+	return nil
+}
+
 func (h *Hub) Run() {
+	h.readyOnce.Do(func() { close(h.ready) })
+
 	for {
 		select {
 		case client := <-h.register:
@@ -76,8 +158,8 @@ func (h *Hub) Run() {
 				select {
 				case client.send <- message:
 				default:
-					close(client.send)
-					delete(h.clients, client)
+					// Only unregister closes send, the client's worker may still reply on it
+					client.conn.Close()
 				}
 			}
 		}
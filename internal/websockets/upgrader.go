@@ -2,29 +2,22 @@ package websockets
 
 import (
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-// Upgrader is the WebSocket upgrader configuration
-var Upgrader = websocket.Upgrader{
+// upgraderMu guards upgrader, which the setters below change while connections are being upgraded
+var upgraderMu sync.RWMutex
+
+// upgrader is the WebSocket upgrader configuration, use Upgrade to upgrade connections with it
+var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	// CheckOrigin controls cross-origin requests
+	// CheckOrigin controls cross-origin requests, SetAllowedOrigins restricts it
 	CheckOrigin: func(r *http.Request) bool {
-		// For development, allow all origins
-		// In production, you should restrict this to your specific domain(s)
-		// For a Raspberry Pi deployment on local network, you might want to:
-		// - Allow all origins on the local network
-		// - Or configure specific allowed origins based on your setup
-
-		// Example for production:
-		// origin := r.Header.Get("Origin")
-		// return origin == "http://localhost:3000" ||
-		//        origin == "http://192.168.1.100:3000" ||
-		//        strings.HasPrefix(origin, "http://192.168.")
-
-		return true // Allow all origins for now
+		return true // Allow all origins until an allowlist is configured
 	},
 	// Error handler for upgrade failures
 	Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
@@ -33,20 +26,63 @@ var Upgrader = websocket.Upgrader{
 	},
 }
 
+// Upgrade upgrades an HTTP connection to a WebSocket connection using the current configuration.
+// The server's read and write timeouts are cleared first, the client pumps set their own deadlines.
+func Upgrade(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (*websocket.Conn, error) {
+	upgraderMu.RLock()
+	u := upgrader
+	upgraderMu.RUnlock()
+
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	return u.Upgrade(w, r, responseHeader)
+}
+
 // Additional configuration options that can be set if needed:
 
 // SetBufferSizes updates the read and write buffer sizes
 func SetBufferSizes(readBufferSize, writeBufferSize int) {
-	Upgrader.ReadBufferSize = readBufferSize
-	Upgrader.WriteBufferSize = writeBufferSize
+	upgraderMu.Lock()
+	defer upgraderMu.Unlock()
+
+	upgrader.ReadBufferSize = readBufferSize
+	upgrader.WriteBufferSize = writeBufferSize
 }
 
 // SetCheckOrigin updates the CheckOrigin function
 func SetCheckOrigin(checkOrigin func(r *http.Request) bool) {
-	Upgrader.CheckOrigin = checkOrigin
+	upgraderMu.Lock()
+	defer upgraderMu.Unlock()
+
+	upgrader.CheckOrigin = checkOrigin
+}
+
+// SetAllowedOrigins only accepts browser connections from the given origins.
+// Requests without an Origin header (non-browser clients) are still accepted,
+// and an empty list allows every origin.
+func SetAllowedOrigins(origins []string) {
+	if len(origins) == 0 {
+		SetCheckOrigin(func(r *http.Request) bool { return true })
+		return
+	}
+
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	SetCheckOrigin(func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || allowed[origin]
+	})
 }
 
 // EnableCompression enables message compression
 func EnableCompression() {
-	Upgrader.EnableCompression = true
+	upgraderMu.Lock()
+	defer upgraderMu.Unlock()
+
+	upgrader.EnableCompression = true
 }
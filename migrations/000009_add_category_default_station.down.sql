@@ -0,0 +1 @@
+ALTER TABLE menu_categories DROP COLUMN IF EXISTS default_station_id;
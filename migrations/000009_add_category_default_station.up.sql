@@ -0,0 +1,2 @@
+ALTER TABLE menu_categories
+ADD COLUMN IF NOT EXISTS default_station_id UUID NULL REFERENCES stations(id) ON DELETE SET NULL;
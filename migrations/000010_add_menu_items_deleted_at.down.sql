@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_menu_items_deleted_at;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS deleted_at;
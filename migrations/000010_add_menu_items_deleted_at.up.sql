@@ -0,0 +1,4 @@
+ALTER TABLE menu_items
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE NULL;
+
+CREATE INDEX IF NOT EXISTS idx_menu_items_deleted_at ON menu_items(deleted_at);
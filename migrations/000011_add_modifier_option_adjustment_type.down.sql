@@ -0,0 +1 @@
+ALTER TABLE modifier_options DROP COLUMN IF EXISTS adjustment_type;
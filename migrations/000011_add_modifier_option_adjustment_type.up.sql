@@ -0,0 +1,2 @@
+ALTER TABLE modifier_options
+ADD COLUMN IF NOT EXISTS adjustment_type VARCHAR(10) NOT NULL DEFAULT 'absolute' CHECK (adjustment_type IN ('absolute', 'percent'));
@@ -0,0 +1,5 @@
+-- Daily counters used to generate order numbers without collisions
+CREATE TABLE IF NOT EXISTS order_number_sequences (
+    business_date DATE PRIMARY KEY,
+    last_value INT NOT NULL DEFAULT 0
+);
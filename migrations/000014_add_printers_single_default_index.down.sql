@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_printers_single_default;
@@ -0,0 +1,12 @@
+-- Keep only the most recently updated default before enforcing uniqueness
+UPDATE printers
+SET is_default = false
+WHERE is_default = true
+  AND id <> (
+    SELECT id FROM printers
+    WHERE is_default = true
+    ORDER BY updated_at DESC
+    LIMIT 1
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_printers_single_default ON printers(is_default) WHERE is_default = true;
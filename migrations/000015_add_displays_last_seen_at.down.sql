@@ -0,0 +1 @@
+ALTER TABLE displays DROP COLUMN IF EXISTS last_seen_at;
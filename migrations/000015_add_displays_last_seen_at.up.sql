@@ -0,0 +1 @@
+ALTER TABLE displays ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP WITH TIME ZONE NULL;
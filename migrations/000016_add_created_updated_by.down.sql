@@ -0,0 +1,5 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS updated_by, DROP COLUMN IF EXISTS created_by;
+ALTER TABLE stations DROP COLUMN IF EXISTS updated_by, DROP COLUMN IF EXISTS created_by;
+ALTER TABLE printers DROP COLUMN IF EXISTS updated_by, DROP COLUMN IF EXISTS created_by;
+ALTER TABLE modifiers DROP COLUMN IF EXISTS updated_by, DROP COLUMN IF EXISTS created_by;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS updated_by, DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,22 @@
+ALTER TABLE menu_items
+    ADD COLUMN IF NOT EXISTS created_by UUID NULL REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS updated_by UUID NULL REFERENCES users(id);
+
+ALTER TABLE modifiers
+    ADD COLUMN IF NOT EXISTS created_by UUID NULL REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS updated_by UUID NULL REFERENCES users(id);
+
+ALTER TABLE printers
+    ADD COLUMN IF NOT EXISTS created_by UUID NULL REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS updated_by UUID NULL REFERENCES users(id);
+
+ALTER TABLE stations
+    ADD COLUMN IF NOT EXISTS created_by UUID NULL REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS updated_by UUID NULL REFERENCES users(id);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS created_by UUID NULL REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS updated_by UUID NULL REFERENCES users(id);
+
+-- Orders already record who took them
+UPDATE orders SET created_by = user_id WHERE created_by IS NULL;
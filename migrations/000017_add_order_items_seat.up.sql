@@ -0,0 +1 @@
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS seat INT NULL CHECK (seat > 0);
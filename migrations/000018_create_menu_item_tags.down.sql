@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS menu_item_tags;
+DROP TABLE IF EXISTS menu_tags;
@@ -0,0 +1,14 @@
+-- Optional managed list; when it has rows, item tags must come from it
+CREATE TABLE IF NOT EXISTS menu_tags (
+    name VARCHAR(50) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS menu_item_tags (
+    menu_item_id UUID NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (menu_item_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_item_tags_tag ON menu_item_tags(tag);
@@ -0,0 +1,2 @@
+ALTER TABLE order_items ALTER COLUMN quantity TYPE INT USING CEIL(quantity);
+ALTER TABLE menu_items DROP COLUMN IF EXISTS unit;
@@ -0,0 +1,3 @@
+-- Items sold by weight are priced per kg and ordered in fractional quantities
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS unit VARCHAR(10) NOT NULL DEFAULT 'each' CHECK (unit IN ('each', 'kg'));
+ALTER TABLE order_items ALTER COLUMN quantity TYPE DECIMAL(10, 3);
@@ -0,0 +1 @@
+ALTER TABLE printers DROP COLUMN IF EXISTS chars_per_line;
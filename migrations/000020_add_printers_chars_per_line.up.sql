@@ -0,0 +1 @@
+ALTER TABLE printers ADD COLUMN IF NOT EXISTS chars_per_line INT NOT NULL DEFAULT 42 CHECK (chars_per_line BETWEEN 24 AND 80);
@@ -0,0 +1,4 @@
+UPDATE order_items SET status = 'in_progress' WHERE status = 'ready';
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_status_check;
+ALTER TABLE order_items ADD CONSTRAINT order_items_status_check
+    CHECK (status IN ('pending', 'in_progress', 'completed', 'cancelled'));
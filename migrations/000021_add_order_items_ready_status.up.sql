@@ -0,0 +1,4 @@
+-- Ready sits between in_progress and completed: cooked, awaiting pickup
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_status_check;
+ALTER TABLE order_items ADD CONSTRAINT order_items_status_check
+    CHECK (status IN ('pending', 'in_progress', 'ready', 'completed', 'cancelled'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_logs_action_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_table_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_user_created_at;
@@ -0,0 +1,4 @@
+-- Audit queries filter on one column and page newest first
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user_created_at ON audit_logs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_table_created_at ON audit_logs(table_name, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action_created_at ON audit_logs(action, created_at DESC);
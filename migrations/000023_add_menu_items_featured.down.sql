@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_menu_items_featured;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS ordering_weight;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS is_featured;
@@ -0,0 +1,4 @@
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS is_featured BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS ordering_weight INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_menu_items_featured ON menu_items(is_featured, ordering_weight DESC) WHERE deleted_at IS NULL;
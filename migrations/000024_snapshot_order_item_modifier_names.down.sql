@@ -0,0 +1,15 @@
+-- Ordered modifiers whose option has since been deleted can't satisfy the old NOT NULL
+-- constraint; refuse to roll back rather than drop them from order history
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM order_item_modifiers WHERE modifier_option_id IS NULL) THEN
+        RAISE EXCEPTION 'order_item_modifiers has rows for deleted modifier options, restore the options before rolling back';
+    END IF;
+END $$;
+
+ALTER TABLE order_item_modifiers DROP CONSTRAINT IF EXISTS order_item_modifiers_modifier_option_id_fkey;
+ALTER TABLE order_item_modifiers ADD CONSTRAINT order_item_modifiers_modifier_option_id_fkey
+    FOREIGN KEY (modifier_option_id) REFERENCES modifier_options(id);
+ALTER TABLE order_item_modifiers ALTER COLUMN modifier_option_id SET NOT NULL;
+
+ALTER TABLE order_item_modifiers DROP COLUMN IF EXISTS name;
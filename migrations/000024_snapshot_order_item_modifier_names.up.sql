@@ -0,0 +1,16 @@
+-- Keep the option name as ordered so later edits or deletes don't rewrite history
+ALTER TABLE order_item_modifiers ADD COLUMN IF NOT EXISTS name VARCHAR(100);
+
+UPDATE order_item_modifiers oim
+SET name = mo.name
+FROM modifier_options mo
+WHERE oim.modifier_option_id = mo.id AND oim.name IS NULL;
+
+UPDATE order_item_modifiers SET name = '' WHERE name IS NULL;
+ALTER TABLE order_item_modifiers ALTER COLUMN name SET NOT NULL;
+
+-- Options can now be deleted once ordered
+ALTER TABLE order_item_modifiers ALTER COLUMN modifier_option_id DROP NOT NULL;
+ALTER TABLE order_item_modifiers DROP CONSTRAINT IF EXISTS order_item_modifiers_modifier_option_id_fkey;
+ALTER TABLE order_item_modifiers ADD CONSTRAINT order_item_modifiers_modifier_option_id_fkey
+    FOREIGN KEY (modifier_option_id) REFERENCES modifier_options(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+-- Imported and reset accounts must pick their own password on first login
+ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
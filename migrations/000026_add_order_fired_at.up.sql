@@ -0,0 +1,12 @@
+-- When the kitchen first started on the order, as opposed to when it was taken
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fired_at TIMESTAMP WITH TIME ZONE NULL;
+
+UPDATE orders o
+SET fired_at = fired.at
+FROM (
+    SELECT order_id, MIN(sent_to_station_at) AS at
+    FROM order_items
+    WHERE sent_to_station_at IS NOT NULL
+    GROUP BY order_id
+) fired
+WHERE o.id = fired.order_id AND o.fired_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE modifiers DROP CONSTRAINT IF EXISTS modifiers_selection_limits_check;
+ALTER TABLE modifiers DROP COLUMN IF EXISTS max_selections;
+ALTER TABLE modifiers DROP COLUMN IF EXISTS min_selections;
@@ -0,0 +1,9 @@
+-- How many options may be chosen from a modifier group, NULL max means no limit
+ALTER TABLE modifiers ADD COLUMN IF NOT EXISTS min_selections INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE modifiers ADD COLUMN IF NOT EXISTS max_selections INTEGER NULL;
+
+-- Single-select groups already allowed only one option
+UPDATE modifiers SET max_selections = 1 WHERE is_multiple = FALSE AND max_selections IS NULL;
+
+ALTER TABLE modifiers ADD CONSTRAINT modifiers_selection_limits_check
+    CHECK (min_selections >= 0 AND (max_selections IS NULL OR max_selections >= GREATEST(min_selections, 1)));
@@ -0,0 +1,4 @@
+UPDATE orders SET status = 'completed', completed_at = COALESCE(completed_at, updated_at) WHERE status = 'ready';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('new', 'in_progress', 'completed', 'cancelled'));
@@ -0,0 +1,4 @@
+-- Ready: every item is done but the order waits for an explicit close
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('new', 'in_progress', 'ready', 'completed', 'cancelled'));
@@ -0,0 +1,2 @@
+-- Rushed orders jump the queue on station feeds
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS is_rush BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,15 @@
+-- A station can send to one printer per role, e.g. a ticket printer and a label printer
+CREATE TABLE IF NOT EXISTS station_printers (
+    station_id UUID NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    printer_id UUID NOT NULL REFERENCES printers(id),
+    role VARCHAR(20) NOT NULL CHECK (role IN ('ticket', 'label', 'receipt')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (station_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_station_printers_printer_id ON station_printers(printer_id);
+
+-- Existing single printers become the station's ticket printer
+INSERT INTO station_printers (station_id, printer_id, role)
+SELECT id, printer_id, 'ticket' FROM stations WHERE printer_id IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+-- Running counter for order number formats without {date}, which can't reset daily
+CREATE SEQUENCE IF NOT EXISTS order_number_seq;
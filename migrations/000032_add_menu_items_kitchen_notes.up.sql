@@ -0,0 +1,2 @@
+-- Preparation notes shown on station feeds only, never to customers
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS kitchen_notes TEXT NULL;
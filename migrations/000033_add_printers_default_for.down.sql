@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_printers_default_for;
+ALTER TABLE printers DROP COLUMN IF EXISTS default_for;
@@ -0,0 +1,7 @@
+-- A printer can be the default for one purpose, with at most one default per purpose
+ALTER TABLE printers ADD COLUMN IF NOT EXISTS default_for VARCHAR(20) NULL CHECK (default_for IN ('receipt', 'kitchen', 'label'));
+
+-- The existing single default becomes the receipt default, is_default now mirrors default_for = 'receipt'
+UPDATE printers SET default_for = 'receipt' WHERE is_default = true;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_printers_default_for ON printers(default_for) WHERE default_for IS NOT NULL;
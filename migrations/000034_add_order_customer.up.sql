@@ -0,0 +1,3 @@
+-- Who a call-ahead or delivery order is for
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_name VARCHAR(100) NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_phone VARCHAR(20) NULL;
@@ -0,0 +1,11 @@
+-- Previous prices of menu items, written by bulk price adjustments
+CREATE TABLE IF NOT EXISTS menu_item_price_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    old_price DECIMAL(10, 2) NOT NULL,
+    new_price DECIMAL(10, 2) NOT NULL,
+    changed_by UUID NULL REFERENCES users(id),
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_menu_item_price_history_item ON menu_item_price_history(menu_item_id, changed_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_orders_provider_external_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS external_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS provider;
+ALTER TABLE orders DROP COLUMN IF EXISTS source;
+
+DROP INDEX IF EXISTS idx_menu_items_plu;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS plu;
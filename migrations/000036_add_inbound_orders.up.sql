@@ -0,0 +1,9 @@
+-- Platform product codes, used to map inbound order lines to menu items
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS plu VARCHAR(32) NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_menu_items_plu ON menu_items(plu) WHERE deleted_at IS NULL AND plu IS NOT NULL;
+
+-- Where an order came from, and the platform's own ID for it
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'pos' CHECK (source IN ('pos', 'online'));
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS provider VARCHAR(50) NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS external_id VARCHAR(100) NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_provider_external_id ON orders(provider, external_id) WHERE external_id IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS released_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS held_at;
@@ -0,0 +1,3 @@
+-- A held order stays off the station feeds until it is released
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS held_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS released_at TIMESTAMP WITH TIME ZONE NULL;
@@ -0,0 +1,2 @@
+-- Manual position of an item in its station feed, set by a cook, lowest first
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS display_priority INTEGER NULL;
@@ -0,0 +1,2 @@
+-- Rushed items jump the queue on station feeds like items of rushed orders
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS is_rush BOOLEAN NOT NULL DEFAULT FALSE;
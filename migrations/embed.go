@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations so the server binary can run
+// them without the migrations directory being present on disk.
+package migrations
+
+import "embed"
+
+// FS contains all up and down migration files
+//
+//go:embed *.sql
+var FS embed.FS